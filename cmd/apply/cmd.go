@@ -0,0 +1,189 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"fmt"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	clusterKey string
+	file       string
+	prune      bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a cluster configuration",
+	Long: "Create, update and delete the machine pools, identity providers, ingresses, add-ons " +
+		"and users of a cluster so that they match a configuration file. The file has the format " +
+		"created by 'rosa export cluster'. Use 'rosa diff' to review the changes first.\n\n" +
+		"The secrets of identity providers are read from the environment variables referenced " +
+		"in the file. Secrets of existing identity providers are only updated when the variable is set.",
+	Example: `  # Make cluster "mycluster" match "cluster.yaml"
+  rosa apply --cluster=mycluster -f cluster.yaml
+
+  # Also delete the resources that aren't in the file
+  rosa apply --cluster=mycluster -f cluster.yaml --prune`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	arguments.AddProfileFlag(flags)
+	confirm.AddFlag(flags)
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to configure. Defaults to the cluster named in the file.",
+	)
+	flags.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"Path of the file that contains the desired configuration (required).",
+	)
+	Cmd.MarkFlagRequired("file")
+	flags.BoolVar(
+		&args.prune,
+		"prune",
+		false,
+		"Delete the resources of the cluster that aren't in the file.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	config, err := ocm.LoadClusterConfig(args.file)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if clusterKey == "" {
+		clusterKey = config.Cluster
+	}
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+	if config.Upgrade != nil {
+		reporter.Warnf("The upgrade policy in '%s' is ignored, use 'rosa edit upgrade' to change it", args.file)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	changes, err := ocmClient.PlanClusterConfig(cluster, awsCreator, config, args.prune)
+	if err != nil {
+		reporter.Errorf("Failed to compare cluster '%s' with '%s': %v", clusterKey, args.file, err)
+		os.Exit(1)
+	}
+
+	if len(changes) == 0 {
+		reporter.Infof("Cluster '%s' matches the configuration in '%s'", clusterKey, args.file)
+		os.Exit(0)
+	}
+	fmt.Print(ocm.FormatChanges(changes))
+	fmt.Println()
+
+	if !confirm.Confirm("apply these changes to cluster '%s'", clusterKey) {
+		os.Exit(0)
+	}
+
+	failed := false
+	for _, change := range changes {
+		reporter.Debugf("Applying change: %s", change)
+		err = change.Apply()
+		if err != nil {
+			reporter.Errorf("Failed to %s %s '%s': %v", change.Action, change.Kind, change.Name, err)
+			failed = true
+			continue
+		}
+		reporter.Infof("Applied: %s", change)
+	}
+	if failed {
+		reporter.Errorf("Configuration in '%s' was partially applied to cluster '%s'", args.file, clusterKey)
+		os.Exit(1)
+	}
+	reporter.Infof("Applied configuration in '%s' to cluster '%s'", args.file, clusterKey)
+}
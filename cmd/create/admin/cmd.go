@@ -17,8 +17,6 @@ limitations under the License.
 package admin
 
 import (
-	"crypto/rand"
-	"math/big"
 	"os"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -127,7 +125,7 @@ func run(cmd *cobra.Command, _ []string) {
 
 	// TODO: Verify that the user does not already exist
 
-	password, err := generateRandomPassword(23)
+	password, err := ocm.GenerateRandomPassword(23)
 	if err != nil {
 		reporter.Errorf("Failed to generate a random password")
 		os.Exit(1)
@@ -179,40 +177,7 @@ func run(cmd *cobra.Command, _ []string) {
 		"If you lose this password you can delete and recreate the cluster admin user.")
 	reporter.Infof("To login, run the following command:\n\n"+
 		"   oc login %s --username %s --password %s\n", cluster.API().URL(), username, password)
+	reporter.Infof("To add a context for the cluster to your kubeconfig file, run the following command:\n\n"+
+		"   rosa create kubeconfig -c %s --username %s\n", clusterKey, username)
 	reporter.Infof("It may take up to a minute for the account to become active.")
 }
-
-func generateRandomPassword(length int) (string, error) {
-	const (
-		lowerLetters = "abcdefghijkmnopqrstuvwxyz"
-		upperLetters = "ABCDEFGHIJKLMNPQRSTUVWXYZ"
-		digits       = "23456789"
-		all          = lowerLetters + upperLetters + digits
-	)
-	var password string
-	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
-		if err != nil {
-			return "", err
-		}
-		newchar := string(all[n.Int64()])
-		if password == "" {
-			password = newchar
-		}
-		if i < length-1 {
-			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
-			if err != nil {
-				return "", err
-			}
-			j := n.Int64()
-			password = password[0:j] + newchar + password[j:]
-		}
-	}
-
-	pw := []rune(password)
-	for _, replace := range []int{5, 11, 17} {
-		pw[replace] = '-'
-	}
-
-	return string(pw), nil
-}
@@ -25,10 +25,12 @@ import (
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ldap"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/reporter"
@@ -61,15 +63,13 @@ var args struct {
 	// Google
 	googleHostedDomain string
 
+	// HTPasswd
+	htpasswdUsername string
+	htpasswdPassword string
+
 	// LDAP
-	ldapURL          string
-	ldapInsecure     bool
-	ldapBindDN       string
-	ldapBindPassword string
-	ldapIDs          string
-	ldapUsernames    string
-	ldapDisplayNames string
-	ldapEmails       string
+	ldap           ldap.Flags
+	ldapVerifyUser string
 
 	// OpenID
 	openidIssuerURL string
@@ -77,9 +77,10 @@ var args struct {
 	openidName      string
 	openidUsername  string
 	openidScopes    string
+	openidSkipCheck bool
 }
 
-var validIdps []string = []string{"github", "gitlab", "google", "ldap", "openid"}
+var validIdps []string = []string{"github", "gitlab", "google", "htpasswd", "ldap", "openid"}
 var validMappingMethods []string = []string{"add", "claim", "generate", "lookup"}
 
 var idRE = regexp.MustCompile(`(?i)^[0-9a-z]+([-_][0-9a-z]+)*$`)
@@ -91,6 +92,9 @@ var Cmd = &cobra.Command{
 	Example: `  # Add a GitHub identity provider to a cluster named "mycluster"
   rosa create idp --type=github --cluster=mycluster
 
+  # Add an htpasswd identity provider with a single user to a cluster named "mycluster"
+  rosa create idp --type=htpasswd --cluster=mycluster --username=myuser --password=mypassword
+
   # Add an identity provider following interactive prompts
   rosa create idp --cluster=mycluster --interactive`,
 	Run: run,
@@ -125,6 +129,14 @@ func init() {
 		"Name for the identity provider.\n",
 	)
 
+	AddFlags(flags)
+
+	interactive.AddFlag(flags)
+}
+
+// AddFlags adds the flags used to configure each type of identity provider to the given set of
+// command line flags.
+func AddFlags(flags *pflag.FlagSet) {
 	flags.StringVar(
 		&args.mappingMethod,
 		"mapping-method",
@@ -187,55 +199,28 @@ func init() {
 		"Google: Restrict users to a Google Apps domain.\n",
 	)
 
-	// LDAP
+	// HTPasswd
 	flags.StringVar(
-		&args.ldapURL,
-		"url",
+		&args.htpasswdUsername,
+		"username",
 		"",
-		"LDAP: An RFC 2255 URL which specifies the LDAP search parameters to use.",
-	)
-	flags.BoolVar(
-		&args.ldapInsecure,
-		"insecure",
-		false,
-		"LDAP: Do not make TLS connections to the server.",
+		"HTPasswd: Username of the user that can log in.",
 	)
 	flags.StringVar(
-		&args.ldapBindDN,
-		"bind-dn",
+		&args.htpasswdPassword,
+		"password",
 		"",
-		"LDAP: DN to bind with during the search phase.",
-	)
-	flags.StringVar(
-		&args.ldapBindPassword,
-		"bind-password",
-		"",
-		"LDAP: Password to bind with during the search phase.",
-	)
-	flags.StringVar(
-		&args.ldapIDs,
-		"id-attributes",
-		"dn",
-		"LDAP: The list of attributes whose values should be used as the user ID.",
-	)
-	flags.StringVar(
-		&args.ldapUsernames,
-		"username-attributes",
-		"uid",
-		"LDAP: The list of attributes whose values should be used as the preferred username.",
-	)
-	flags.StringVar(
-		&args.ldapDisplayNames,
-		"name-attributes",
-		"cn",
-		"LDAP: The list of attributes whose values should be used as the display name.",
+		"HTPasswd: Password of the user that can log in.\n",
 	)
+
+	// LDAP
 	flags.StringVar(
-		&args.ldapEmails,
-		"email-attributes",
+		&args.ldapVerifyUser,
+		"verify-user",
 		"",
-		"LDAP: The list of attributes whose values should be used as the email address.\n",
+		"LDAP: Username to look up on the LDAP server to verify the configuration before applying it.",
 	)
+	ldap.AddFlags(flags, &args.ldap)
 
 	// OpenID
 	flags.StringVar(
@@ -267,10 +252,15 @@ func init() {
 		&args.openidScopes,
 		"extra-scopes",
 		"",
-		"OpenID: List of scopes to request, in addition to the 'openid' scope, during the authorization token request.\n",
+		"OpenID: List of scopes to request, in addition to the 'openid' scope, during the authorization token request.",
+	)
+	flags.BoolVar(
+		&args.openidSkipCheck,
+		"skip-issuer-validation",
+		false,
+		"OpenID: Don't check the discovery document of the issuer, for issuers that aren't reachable "+
+			"from this machine.\n",
 	)
-
-	interactive.AddFlag(flags)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -407,19 +397,7 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 	idpName = strings.Trim(idpName, " \t")
 
-	var idpBuilder cmv1.IdentityProviderBuilder
-	switch idpType {
-	case "github":
-		idpBuilder, err = buildGithubIdp(cmd, cluster, idpName)
-	case "gitlab":
-		idpBuilder, err = buildGitlabIdp(cmd, cluster, idpName)
-	case "google":
-		idpBuilder, err = buildGoogleIdp(cmd, cluster, idpName)
-	case "ldap":
-		idpBuilder, err = buildLdapIdp(cmd, cluster, idpName)
-	case "openid":
-		idpBuilder, err = buildOpenidIdp(cmd, cluster, idpName)
-	}
+	idpBuilder, err := BuildIdentityProvider(cmd, cluster, idpType, idpName, BuildOptions{})
 	if err != nil {
 		reporter.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
 		os.Exit(1)
@@ -448,6 +426,27 @@ func run(cmd *cobra.Command, _ []string) {
 	)
 }
 
+// BuildIdentityProvider returns a builder for an identity provider of the given type, using the
+// values of the command line flags and prompting for any missing ones.
+func BuildIdentityProvider(cmd *cobra.Command, cluster *cmv1.Cluster, idpType string,
+	idpName string, options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	switch idpType {
+	case "github":
+		return buildGithubIdp(cmd, cluster, idpName, options)
+	case "gitlab":
+		return buildGitlabIdp(cmd, cluster, idpName, options)
+	case "google":
+		return buildGoogleIdp(cmd, cluster, idpName, options)
+	case "htpasswd":
+		return buildHtpasswdIdp(cmd, cluster, idpName, options)
+	case "ldap":
+		return buildLdapIdp(cmd, cluster, idpName, options)
+	case "openid":
+		return buildOpenidIdp(cmd, cluster, idpName, options)
+	}
+	return idpBuilder, fmt.Errorf("Expected a valid IDP type. Options are %s", validIdps)
+}
+
 func GenerateIdpName(idpType string, idps []IdentityProvider) string {
 	nextSuffix := 0
 	for _, idp := range idps {
@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// openIDConfiguration contains the fields of the OpenID provider metadata that are checked
+// before creating the identity provider.
+type openIDConfiguration struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	ClaimsSupported       []string `json:"claims_supported"`
+	ScopesSupported       []string `json:"scopes_supported"`
+}
+
+// ValidateOpenIDIssuer fetches the discovery document of the given issuer and checks that it
+// describes a usable provider for the given claims and extra scopes. When a CA bundle is given
+// it is the only one trusted to verify the certificate chain of the issuer.
+func ValidateOpenIDIssuer(issuerURL string, ca string, claims []string, scopes []string) error {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if ca != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return errors.New("Expected a valid certificate bundle: no PEM certificates found")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs: pool,
+		}
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   10 * time.Second,
+	}
+
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + discoveryPath
+	response, err := client.Get(discoveryURL)
+	if err != nil {
+		var certErr x509.UnknownAuthorityError
+		if errors.As(err, &certErr) && ca != "" {
+			return fmt.Errorf("Certificate of OpenID issuer '%s' isn't signed by the given CA", issuerURL)
+		}
+		return fmt.Errorf("Failed to get OpenID configuration from '%s': %v", discoveryURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed to get OpenID configuration from '%s': %s", discoveryURL, response.Status)
+	}
+
+	var config openIDConfiguration
+	err = json.NewDecoder(response.Body).Decode(&config)
+	if err != nil {
+		return fmt.Errorf("Failed to parse OpenID configuration from '%s': %v", discoveryURL, err)
+	}
+
+	if strings.TrimSuffix(config.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return fmt.Errorf("OpenID issuer '%s' doesn't match the issuer in its configuration '%s'",
+			issuerURL, config.Issuer)
+	}
+	for name, endpoint := range map[string]string{
+		"authorization": config.AuthorizationEndpoint,
+		"token":         config.TokenEndpoint,
+	} {
+		if endpoint == "" {
+			return fmt.Errorf("OpenID configuration of '%s' has no %s endpoint", issuerURL, name)
+		}
+		parsedURL, err := url.ParseRequestURI(endpoint)
+		if err != nil || parsedURL.Scheme != "https" {
+			return fmt.Errorf("Expected OpenID %s endpoint '%s' to use an https:// scheme", name, endpoint)
+		}
+	}
+
+	// Providers aren't required to publish the supported claims and scopes, so only check
+	// them when they are listed:
+	if len(config.ClaimsSupported) > 0 {
+		missing := missingValues(claims, config.ClaimsSupported)
+		if len(missing) > 0 {
+			return fmt.Errorf("OpenID issuer '%s' doesn't support claims %s, supported claims are %s",
+				issuerURL, missing, config.ClaimsSupported)
+		}
+	}
+	if len(config.ScopesSupported) > 0 {
+		missing := missingValues(scopes, config.ScopesSupported)
+		if len(missing) > 0 {
+			return fmt.Errorf("OpenID issuer '%s' doesn't support scopes %s, supported scopes are %s",
+				issuerURL, missing, config.ScopesSupported)
+		}
+	}
+
+	return nil
+}
+
+func missingValues(values []string, supported []string) []string {
+	missing := []string{}
+	for _, value := range values {
+		found := false
+		for _, item := range supported {
+			if value == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
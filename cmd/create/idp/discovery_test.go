@@ -0,0 +1,129 @@
+package idp_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/cmd/create/idp"
+)
+
+var _ = Describe("ValidateOpenIDIssuer", func() {
+	var (
+		server *httptest.Server
+		config map[string]interface{}
+		ca     string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/.well-known/openid-configuration" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(config)
+		}))
+		config = map[string]interface{}{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"claims_supported":       []string{"sub", "email", "name", "preferred_username"},
+			"scopes_supported":       []string{"openid", "email", "profile"},
+		}
+		ca = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		}))
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("accepts a valid configuration", func() {
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"email", "name"}, []string{"profile"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("accepts an issuer with a trailing slash", func() {
+		err := idp.ValidateOpenIDIssuer(server.URL+"/", ca, []string{"email"}, []string{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("fails when the issuer doesn't match", func() {
+		config["issuer"] = "https://example.com"
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"email"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("doesn't match the issuer")))
+	})
+
+	It("fails when the discovery document doesn't exist", func() {
+		err := idp.ValidateOpenIDIssuer(server.URL+"/typo", ca, []string{"email"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("fails when the token endpoint is missing", func() {
+		delete(config, "token_endpoint")
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"email"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("no token endpoint")))
+	})
+
+	It("fails when an endpoint doesn't use https", func() {
+		config["authorization_endpoint"] = "http://example.com/authorize"
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"email"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("https://")))
+	})
+
+	It("fails when a claim isn't supported", func() {
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"email", "upn"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("doesn't support claims [upn]")))
+	})
+
+	It("fails when a scope isn't supported", func() {
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"email"}, []string{"groups"})
+		Expect(err).To(MatchError(ContainSubstring("doesn't support scopes [groups]")))
+	})
+
+	It("skips claim and scope checks when the provider doesn't list them", func() {
+		delete(config, "claims_supported")
+		delete(config, "scopes_supported")
+		err := idp.ValidateOpenIDIssuer(server.URL, ca, []string{"upn"}, []string{"groups"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("fails when the CA doesn't sign the issuer certificate", func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "Other CA"},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			KeyUsage:              x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+		}
+		cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).ToNot(HaveOccurred())
+		otherCA := string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert,
+		}))
+		err = idp.ValidateOpenIDIssuer(server.URL, otherCA, []string{"email"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("isn't signed by the given CA")))
+	})
+
+	It("fails when the CA file has no certificates", func() {
+		err := idp.ValidateOpenIDIssuer(server.URL, "not a certificate", []string{"email"}, []string{})
+		Expect(err).To(MatchError(ContainSubstring("no PEM certificates")))
+	})
+})
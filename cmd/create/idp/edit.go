@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// BuildOptions contains the options used by the identity provider builders that don't come from
+// the command line flags.
+type BuildOptions struct {
+	// When editing an existing identity provider secrets are optional, as the API doesn't return
+	// them and the update keeps the current ones when they aren't sent.
+	Editing bool
+
+	// CA of the identity provider being edited, kept when no new CA file is given
+	CurrentCA string
+}
+
+func needsSecret(secret string, options BuildOptions) bool {
+	return secret == "" && !options.Editing
+}
+
+// SetDefaults uses the values of an existing identity provider for all the flags that weren't
+// given in the command line, and returns the options needed to use the builders to update it.
+func SetDefaults(cmd *cobra.Command, idp *cmv1.IdentityProvider) BuildOptions {
+	options := BuildOptions{
+		Editing: true,
+	}
+	flags := cmd.Flags()
+	setString := func(flag string, target *string, values ...string) {
+		if !flags.Changed(flag) {
+			*target = strings.Join(values, ",")
+		}
+	}
+
+	setString("mapping-method", &args.mappingMethod, string(idp.MappingMethod()))
+
+	switch ocm.IdentityProviderType(idp) {
+	case "GitHub":
+		github := idp.Github()
+		setString("client-id", &args.clientID, github.ClientID())
+		setString("hostname", &args.githubHostname, github.Hostname())
+		if !flags.Changed("organizations") && !flags.Changed("teams") {
+			args.githubOrganizations = strings.Join(github.Organizations(), ",")
+			args.githubTeams = strings.Join(github.Teams(), ",")
+		}
+		options.CurrentCA = github.CA()
+	case "GitLab":
+		gitlab := idp.Gitlab()
+		setString("client-id", &args.clientID, gitlab.ClientID())
+		setString("host-url", &args.gitlabURL, gitlab.URL())
+		options.CurrentCA = gitlab.CA()
+	case "Google":
+		google := idp.Google()
+		setString("client-id", &args.clientID, google.ClientID())
+		setString("hosted-domain", &args.googleHostedDomain, google.HostedDomain())
+	case "htpasswd":
+		setString("username", &args.htpasswdUsername, idp.Htpasswd().Username())
+	case "LDAP":
+		ldap := idp.LDAP()
+		setString("url", &args.ldap.URL, ldap.URL())
+		if !flags.Changed("insecure") {
+			args.ldap.Insecure = ldap.Insecure()
+		}
+		setString("bind-dn", &args.ldap.BindDN, ldap.BindDN())
+		setString("id-attributes", &args.ldap.IDs, ldap.Attributes().ID()...)
+		setString("username-attributes", &args.ldap.PreferredUsernames, ldap.Attributes().PreferredUsername()...)
+		setString("name-attributes", &args.ldap.Names, ldap.Attributes().Name()...)
+		setString("email-attributes", &args.ldap.Emails, ldap.Attributes().Email()...)
+		options.CurrentCA = ldap.CA()
+	case "OpenID":
+		openID := idp.OpenID()
+		setString("client-id", &args.clientID, openID.ClientID())
+		setString("issuer-url", &args.openidIssuerURL, openID.Issuer())
+		setString("email-claims", &args.openidEmail, openID.Claims().Email()...)
+		setString("name-claims", &args.openidName, openID.Claims().Name()...)
+		setString("username-claims", &args.openidUsername, openID.Claims().PreferredUsername()...)
+		setString("extra-scopes", &args.openidScopes, openID.ExtraScopes()...)
+		options.CurrentCA = openID.CA()
+	}
+
+	return options
+}
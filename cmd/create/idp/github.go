@@ -31,7 +31,8 @@ import (
 
 func buildGithubIdp(cmd *cobra.Command,
 	cluster *cmv1.Cluster,
-	idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	idpName string,
+	options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
 	organizations := args.githubOrganizations
 	teams := args.githubTeams
 
@@ -94,7 +95,7 @@ func buildGithubIdp(cmd *cobra.Command,
 
 	clientID := args.clientID
 	clientSecret := args.clientSecret
-	if clientID == "" || clientSecret == "" {
+	if clientID == "" || needsSecret(clientSecret, options) {
 		// Create the full URL to automatically generate the GitHub app info
 		registerURLBase := "https://github.com/settings/applications/new"
 
@@ -143,7 +144,7 @@ func buildGithubIdp(cmd *cobra.Command,
 			return idpBuilder, errors.New("Expected a GitHub application Client ID")
 		}
 
-		if clientSecret == "" {
+		if needsSecret(clientSecret, options) {
 			clientSecret, err = interactive.GetPassword(interactive.Input{
 				Question: "Client Secret",
 				Help:     "Paste the Client Secret provided by GitHub when registering your application.",
@@ -157,8 +158,10 @@ func buildGithubIdp(cmd *cobra.Command,
 
 	// Create GitHub IDP
 	githubIDP := cmv1.NewGithubIdentityProvider().
-		ClientID(clientID).
-		ClientSecret(clientSecret)
+		ClientID(clientID)
+	if clientSecret != "" {
+		githubIDP = githubIDP.ClientSecret(clientSecret)
+	}
 
 	githubHostname := args.githubHostname
 	if interactive.Enabled() {
@@ -191,7 +194,7 @@ func buildGithubIdp(cmd *cobra.Command,
 			}
 		}
 		// Get certificate contents
-		ca := ""
+		ca := options.CurrentCA
 		if caPath != "" {
 			cert, err := ioutil.ReadFile(caPath)
 			if err != nil {
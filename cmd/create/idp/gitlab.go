@@ -31,12 +31,13 @@ import (
 
 func buildGitlabIdp(cmd *cobra.Command,
 	cluster *cmv1.Cluster,
-	idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	idpName string,
+	options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 	gitlabURL := args.gitlabURL
 
-	if !cmd.Flags().Changed("host-url") {
+	if !cmd.Flags().Changed("host-url") && (interactive.Enabled() || !options.Editing) {
 		gitlabURL, err = interactive.GetString(interactive.Input{
 			Question: "URL",
 			Help:     cmd.Flags().Lookup("host-url").Usage,
@@ -61,7 +62,7 @@ func buildGitlabIdp(cmd *cobra.Command,
 		return idpBuilder, errors.New("GitLab provider URL must not have a fragment")
 	}
 
-	if clientID == "" || clientSecret == "" {
+	if clientID == "" || needsSecret(clientSecret, options) {
 		instructionsURL := fmt.Sprintf("%s/profile/applications", gitlabURL)
 		consoleURL := cluster.Console().URL()
 		oauthURL := strings.Replace(consoleURL, "console-openshift-console", "oauth-openshift", 1)
@@ -94,7 +95,7 @@ func buildGitlabIdp(cmd *cobra.Command,
 			return idpBuilder, errors.New("Expected a GitLab application Application ID")
 		}
 
-		if clientSecret == "" {
+		if needsSecret(clientSecret, options) {
 			clientSecret, err = interactive.GetPassword(interactive.Input{
 				Question: "Secret",
 				Help:     "Paste the Secret provided by GitLab when registering your application.",
@@ -118,7 +119,7 @@ func buildGitlabIdp(cmd *cobra.Command,
 		}
 	}
 	// Get certificate contents
-	ca := ""
+	ca := options.CurrentCA
 	if caPath != "" {
 		cert, err := ioutil.ReadFile(caPath)
 		if err != nil {
@@ -135,8 +136,10 @@ func buildGitlabIdp(cmd *cobra.Command,
 	// Create GitLab IDP
 	gitlabIDP := cmv1.NewGitlabIdentityProvider().
 		ClientID(clientID).
-		ClientSecret(clientSecret).
 		URL(gitlabURL)
+	if clientSecret != "" {
+		gitlabIDP = gitlabIDP.ClientSecret(clientSecret)
+	}
 
 	// Set the CA file, if any
 	if ca != "" {
@@ -30,11 +30,12 @@ import (
 
 func buildGoogleIdp(cmd *cobra.Command,
 	cluster *cmv1.Cluster,
-	idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	idpName string,
+	options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 
-	if clientID == "" || clientSecret == "" {
+	if clientID == "" || needsSecret(clientSecret, options) {
 		instructionsURL := "https://console.developers.google.com/projectcreate"
 		consoleURL := cluster.Console().URL()
 		oauthURL := strings.Replace(consoleURL, "console-openshift-console", "oauth-openshift", 1)
@@ -62,7 +63,7 @@ func buildGoogleIdp(cmd *cobra.Command,
 			return idpBuilder, errors.New("Expected a Google application Client ID")
 		}
 
-		if clientSecret == "" {
+		if needsSecret(clientSecret, options) {
 			clientSecret, err = interactive.GetPassword(interactive.Input{
 				Question: "Client Secret",
 				Help:     "Paste the Client Secret provided by Google when registering your application.",
@@ -81,8 +82,10 @@ func buildGoogleIdp(cmd *cobra.Command,
 
 	// Create Google IDP
 	googleIDP := cmv1.NewGoogleIdentityProvider().
-		ClientID(clientID).
-		ClientSecret(clientSecret)
+		ClientID(clientID)
+	if clientSecret != "" {
+		googleIDP = googleIDP.ClientSecret(clientSecret)
+	}
 
 	hostedDomain := args.googleHostedDomain
 	if interactive.Enabled() || (mappingMethod != "lookup" && hostedDomain == "") {
@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// Names reserved for the admin created with 'rosa create admin'
+const (
+	adminIDPName  = "Cluster-Admin"
+	adminUsername = "cluster-admin"
+)
+
+func buildHtpasswdIdp(cmd *cobra.Command,
+	_ *cmv1.Cluster,
+	idpName string,
+	options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	if strings.EqualFold(idpName, adminIDPName) {
+		return idpBuilder, fmt.Errorf("Name '%s' is reserved for 'rosa create admin'", adminIDPName)
+	}
+
+	username := args.htpasswdUsername
+	password := args.htpasswdPassword
+
+	if interactive.Enabled() || username == "" {
+		username, err = interactive.GetString(interactive.Input{
+			Question: "Username",
+			Help:     cmd.Flags().Lookup("username").Usage,
+			Default:  username,
+			Required: true,
+		})
+		if err != nil {
+			return idpBuilder, errors.New("Expected a valid username")
+		}
+	}
+	if !ocm.IsValidUsername(username) {
+		return idpBuilder, fmt.Errorf("Username '%s' isn't valid: it must contain only letters, "+
+			"digits, dashes and underscores", username)
+	}
+	if username == adminUsername {
+		return idpBuilder, fmt.Errorf("Username '%s' is reserved for 'rosa create admin'", adminUsername)
+	}
+
+	if needsSecret(password, options) {
+		password, err = interactive.GetPassword(interactive.Input{
+			Question: "Password",
+			Help:     cmd.Flags().Lookup("password").Usage,
+			Required: true,
+		})
+		if err != nil {
+			return idpBuilder, errors.New("Expected a valid password")
+		}
+	}
+	if strings.ContainsAny(password, " \t\r\n") {
+		return idpBuilder, errors.New("Password must not contain whitespace")
+	}
+
+	mappingMethod, err := getMappingMethod(cmd, args.mappingMethod)
+	if err != nil {
+		return idpBuilder, err
+	}
+
+	htpasswdIDP := cmv1.NewHTPasswdIdentityProvider().
+		Username(username)
+	if password != "" {
+		htpasswdIDP = htpasswdIDP.Password(password)
+	}
+
+	// Create new IDP with HTPasswd provider
+	idpBuilder.
+		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(idpName).
+		MappingMethod(cmv1.IdentityProviderMappingMethod(mappingMethod)).
+		Htpasswd(htpasswdIDP)
+
+	return
+}
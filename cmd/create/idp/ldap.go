@@ -27,13 +27,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ldap"
 )
 
 func buildLdapIdp(cmd *cobra.Command,
 	_ *cmv1.Cluster,
-	idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
-	ldapURL := args.ldapURL
-	ldapIDs := args.ldapIDs
+	idpName string,
+	options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	ldapURL := args.ldap.URL
+	ldapIDs := args.ldap.IDs
 
 	if ldapURL == "" || ldapIDs == "" {
 		instructionsURL := "https://docs.openshift.com/dedicated/4/authentication/" +
@@ -71,7 +73,7 @@ func buildLdapIdp(cmd *cobra.Command,
 	}
 	needsSecure := parsedLdapURL.Scheme == "ldaps"
 
-	ldapInsecure := args.ldapInsecure
+	ldapInsecure := args.ldap.Insecure
 	if interactive.Enabled() && !needsSecure {
 		ldapInsecure, err = interactive.GetBool(interactive.Input{
 			Question: "Insecure",
@@ -99,6 +101,9 @@ func buildLdapIdp(cmd *cobra.Command,
 	}
 	// Get certificate contents
 	ca := ""
+	if !ldapInsecure {
+		ca = options.CurrentCA
+	}
 	if caPath != "" {
 		if ldapInsecure {
 			return idpBuilder, fmt.Errorf("Cannot use certificate bundle with an insecure connection")
@@ -115,8 +120,8 @@ func buildLdapIdp(cmd *cobra.Command,
 		return idpBuilder, err
 	}
 
-	ldapBindDN := args.ldapBindDN
-	ldapBindPassword := args.ldapBindPassword
+	ldapBindDN := args.ldap.BindDN
+	ldapBindPassword := args.ldap.BindPassword
 	if interactive.Enabled() {
 		ldapBindDN, err = interactive.GetString(interactive.Input{
 			Question: "Bind DN",
@@ -131,7 +136,7 @@ func buildLdapIdp(cmd *cobra.Command,
 			ldapBindPassword, err = interactive.GetPassword(interactive.Input{
 				Question: "Bind password",
 				Help:     cmd.Flags().Lookup("bind-password").Usage,
-				Required: true,
+				Required: !options.Editing,
 			})
 			if err != nil {
 				return idpBuilder, fmt.Errorf("Expected a valid password to bind with: %s", err)
@@ -160,9 +165,9 @@ func buildLdapIdp(cmd *cobra.Command,
 		}
 	}
 
-	ldapUsernames := args.ldapUsernames
-	ldapDisplayNames := args.ldapDisplayNames
-	ldapEmails := args.ldapEmails
+	ldapUsernames := args.ldap.PreferredUsernames
+	ldapDisplayNames := args.ldap.Names
+	ldapEmails := args.ldap.Emails
 	if interactive.Enabled() {
 		ldapUsernames, err = interactive.GetString(interactive.Input{
 			Question: "Preferred username",
@@ -206,6 +211,28 @@ func buildLdapIdp(cmd *cobra.Command,
 		ldapAttributes = ldapAttributes.Email(strings.Split(ldapEmails, ",")...)
 	}
 
+	// Check the configuration against the LDAP server, if requested:
+	if args.ldapVerifyUser != "" {
+		result, err := ldap.Verify(ldap.Config{
+			URL:                ldapURL,
+			Insecure:           ldapInsecure,
+			CA:                 ca,
+			BindDN:             ldapBindDN,
+			BindPassword:       ldapBindPassword,
+			IDs:                ldap.SplitAttributes(ldapIDs),
+			PreferredUsernames: ldap.SplitAttributes(ldapUsernames),
+			Names:              ldap.SplitAttributes(ldapDisplayNames),
+			Emails:             ldap.SplitAttributes(ldapEmails),
+		}, args.ldapVerifyUser)
+		if err != nil {
+			return idpBuilder, fmt.Errorf("Failed to verify LDAP configuration: %v", err)
+		}
+		err = result.Validate()
+		if err != nil {
+			return idpBuilder, fmt.Errorf("Failed to verify LDAP configuration: %v", err)
+		}
+	}
+
 	// Create LDAP IDP
 	ldapIDP := cmv1.NewLDAPIdentityProvider().
 		URL(ldapURL).
@@ -31,7 +31,8 @@ import (
 
 func buildOpenidIdp(cmd *cobra.Command,
 	cluster *cmv1.Cluster,
-	idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	idpName string,
+	options BuildOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 	issuerURL := args.openidIssuerURL
@@ -39,7 +40,7 @@ func buildOpenidIdp(cmd *cobra.Command,
 	name := args.openidName
 	username := args.openidUsername
 
-	isInteractive := clientID == "" || clientSecret == "" || issuerURL == "" ||
+	isInteractive := clientID == "" || needsSecret(clientSecret, options) || issuerURL == "" ||
 		(email == "" && name == "" && username == "")
 
 	if isInteractive {
@@ -73,7 +74,7 @@ func buildOpenidIdp(cmd *cobra.Command,
 		}
 	}
 
-	if isInteractive && clientSecret == "" {
+	if isInteractive && needsSecret(clientSecret, options) {
 		clientSecret, err = interactive.GetPassword(interactive.Input{
 			Question: "Client Secret",
 			Help:     "Paste the Client Secret provided by the OpenID provider when registering your application.",
@@ -121,7 +122,7 @@ func buildOpenidIdp(cmd *cobra.Command,
 		}
 	}
 	// Get certificate contents
-	ca := ""
+	ca := options.CurrentCA
 	if caPath != "" {
 		cert, err := ioutil.ReadFile(caPath)
 		if err != nil {
@@ -198,15 +199,36 @@ func buildOpenidIdp(cmd *cobra.Command,
 		}
 	}
 
+	extraScopes := []string{}
+	if scopes != "" {
+		extraScopes = strings.Split(scopes, ",")
+	}
+
+	// Check that the issuer is reachable and supports the requested claims and scopes:
+	if !args.openidSkipCheck {
+		claims := []string{}
+		for _, value := range []string{email, name, username} {
+			if value != "" {
+				claims = append(claims, strings.Split(value, ",")...)
+			}
+		}
+		err = ValidateOpenIDIssuer(issuerURL, ca, claims, extraScopes)
+		if err != nil {
+			return idpBuilder, fmt.Errorf("%v. Use '--skip-issuer-validation' to skip this check", err)
+		}
+	}
+
 	// Create OpenID IDP
 	openIDIDP := cmv1.NewOpenIDIdentityProvider().
 		ClientID(clientID).
-		ClientSecret(clientSecret).
 		Issuer(issuerURL).
 		Claims(openIDClaims)
+	if clientSecret != "" {
+		openIDIDP = openIDIDP.ClientSecret(clientSecret)
+	}
 
-	if scopes != "" {
-		openIDIDP = openIDIDP.ExtraScopes(strings.Split(scopes, ",")...)
+	if len(extraScopes) > 0 {
+		openIDIDP = openIDIDP.ExtraScopes(extraScopes...)
 	}
 
 	// Set the CA file, if any
@@ -0,0 +1,283 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/kubeconfig"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Name of the identity provider created by 'rosa create admin'
+const adminIDPName = "Cluster-Admin"
+
+var args struct {
+	clusterKey    string
+	username      string
+	passwordStdin bool
+	kubeconfig    string
+	printLogin    bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Create a kubeconfig context to login to the cluster",
+	Long: "Logs in to the cluster and adds a context named after the cluster to the kubeconfig " +
+		"file, using the cluster credentials when available or the admin user created with " +
+		"'rosa create admin'.",
+	Example: `  # Add a context for a cluster named "mycluster" to the default kubeconfig file
+  rosa create kubeconfig --cluster=mycluster
+
+  # Add a context for a user of an htpasswd identity provider to a specific file
+  rosa create kubeconfig --cluster=mycluster --username=myuser --kubeconfig=./mycluster.kubeconfig
+
+  # Add a context for a user whose password is kept in a secret store
+  vault kv get -field=password secret/mycluster | rosa create kubeconfig --cluster=mycluster --password-stdin
+
+  # Print the command to login to the cluster with oc
+  rosa create kubeconfig --cluster=mycluster --print-login`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to create the kubeconfig context for (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.username,
+		"username",
+		"",
+		"Username used to login to the cluster. Defaults to the admin user created with 'rosa create admin'.",
+	)
+
+	flags.BoolVar(
+		&args.passwordStdin,
+		"password-stdin",
+		false,
+		"Read the password used to login to the cluster from standard input. Prompted for when not given.",
+	)
+
+	flags.StringVar(
+		&args.kubeconfig,
+		"kubeconfig",
+		"",
+		"Path of the kubeconfig file to write. Defaults to the first file in $KUBECONFIG or ~/.kube/config.",
+	)
+
+	flags.BoolVar(
+		&args.printLogin,
+		"print-login",
+		false,
+		"Print the 'oc login' command for the cluster instead of writing the kubeconfig file.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	// Read the password before any prompt so that it doesn't compete with other input
+	var password string
+	if args.passwordStdin {
+		if args.printLogin {
+			reporter.Errorf("Option '--password-stdin' can't be used with '--print-login'")
+			os.Exit(1)
+		}
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			reporter.Errorf("Failed to read password from standard input: %v", err)
+			os.Exit(1)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+		if password == "" {
+			reporter.Errorf("Expected a password in standard input")
+			os.Exit(1)
+		}
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	username := args.username
+	if username == "" && (args.printLogin || password != "") {
+		username = getAdminUsername(reporter, ocmClient, cluster, clusterKey)
+	}
+
+	if args.printLogin {
+		// The password is never printed, 'oc login' prompts for it
+		fmt.Printf("oc login %s --username %s\n", cluster.API().URL(), username)
+		os.Exit(0)
+	}
+
+	var entry *kubeconfig.Entry
+
+	// Prefer the cluster credentials unless the user asked to login as a specific user
+	if username == "" {
+		reporter.Debugf("Loading credentials of cluster '%s'", clusterKey)
+		credentials, err := ocmClient.GetClusterCredentials(cluster.ID())
+		if err != nil {
+			reporter.Debugf("Failed to get credentials of cluster '%s': %v", clusterKey, err)
+		} else if credentials.Kubeconfig() != "" {
+			entry, err = kubeconfig.Parse([]byte(credentials.Kubeconfig()))
+			if err != nil {
+				reporter.Errorf("Failed to parse kubeconfig of cluster '%s': %v", clusterKey, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if entry == nil {
+		if username == "" {
+			username = getAdminUsername(reporter, ocmClient, cluster, clusterKey)
+		}
+		if password == "" {
+			password, err = interactive.GetPassword(interactive.Input{
+				Question: fmt.Sprintf("Password for user '%s'", username),
+				Required: true,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid password: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		reporter.Debugf("Logging in to cluster '%s' as user '%s'", clusterKey, username)
+		token, err := kubeconfig.RequestToken(cluster.API().URL(), username, password)
+		if err != nil {
+			reporter.Errorf("Failed to login to cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		entry = &kubeconfig.Entry{
+			Cluster: map[string]interface{}{
+				"server": cluster.API().URL(),
+			},
+			User: map[string]interface{}{
+				"token": token,
+			},
+		}
+	}
+	entry.Name = cluster.Name()
+
+	path := args.kubeconfig
+	if path == "" {
+		path, err = kubeconfig.DefaultPath()
+		if err != nil {
+			reporter.Errorf("Failed to find kubeconfig file: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	err = kubeconfig.Merge(path, entry)
+	if err != nil {
+		reporter.Errorf("Failed to write kubeconfig file '%s': %v", path, err)
+		os.Exit(1)
+	}
+	reporter.Infof("Added context '%s' to kubeconfig file '%s' and made it the current context",
+		entry.Name, path)
+}
+
+// getAdminUsername returns the user of the admin identity provider, exiting when there is none.
+func getAdminUsername(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster,
+	clusterKey string) string {
+	reporter.Debugf("Loading identity providers for cluster '%s'", clusterKey)
+	idps, err := ocmClient.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	for _, idp := range idps {
+		if idp.Name() == adminIDPName && idp.Htpasswd() != nil {
+			return idp.Htpasswd().Username()
+		}
+	}
+	reporter.Errorf("There is no admin on cluster '%s'. To create it run the following command:\n"+
+		"   rosa create admin -c %s\n"+
+		"   Or use '--username' to login as another user", clusterKey, clusterKey)
+	os.Exit(1)
+	return ""
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
@@ -47,6 +48,10 @@ var Cmd = &cobra.Command{
 	},
 }
 
+func init() {
+	output.AddFlag(Cmd)
+}
+
 func run(_ *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
@@ -78,6 +83,15 @@ func run(_ *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	if output.HasFlag() {
+		err = output.Print(addOn)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Print add-on description:
 	fmt.Printf("ADD-ON\n"+
 		"ID:               %s\n"+
@@ -17,6 +17,7 @@ limitations under the License.
 package admin
 
 import (
+	"fmt"
 	"os"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -25,18 +26,29 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
 const (
 	idpName = "Cluster-Admin"
-	// username = "cluster-admin"
+	group   = "cluster-admins"
 )
 
 var args struct {
 	clusterKey string
 }
 
+// adminDescription is the representation of the cluster-admin user used by the '--output' flag.
+type adminDescription struct {
+	Username           string `json:"username"`
+	IdentityProvider   string `json:"identity_provider"`
+	IdentityProviderID string `json:"identity_provider_id"`
+	Group              string `json:"group"`
+	MemberOfGroup      bool   `json:"member_of_group"`
+	APIURL             string `json:"api_url"`
+}
+
 var Cmd = &cobra.Command{
 	Use:   "admin",
 	Short: "Show details of the cluster-admin user",
@@ -57,6 +69,8 @@ func init() {
 		"Name or ID of the cluster that cluster-admin belongs to.",
 	)
 	Cmd.MarkFlagRequired("cluster")
+
+	output.AddFlag(Cmd)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -128,7 +142,7 @@ func run(cmd *cobra.Command, _ []string) {
 
 	var idp *cmv1.IdentityProvider
 	for _, item := range idps {
-		if ocm.IdentityProviderType(item) == "htpasswd" {
+		if ocm.IdentityProviderType(item) == "htpasswd" && item.Name() == idpName {
 			idp = item
 		}
 	}
@@ -138,6 +152,56 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(0)
 	}
 
-	reporter.Infof("There is an admin on cluster '%s'. To login, run the following command:\n"+
-		"   oc login %s --username %s", clusterKey, cluster.API().URL(), idp.Htpasswd().Username())
+	username := idp.Htpasswd().Username()
+	reporter.Debugf("Loading '%s' user from %s group on cluster '%s'", username, group, clusterKey)
+	user, err := ocmClient.GetUser(cluster.ID(), group, username)
+	if err != nil {
+		reporter.Errorf("Failed to get '%s' user for cluster '%s': %v", username, clusterKey, err)
+		os.Exit(1)
+	}
+
+	if output.HasFlag() {
+		err = output.Print(&adminDescription{
+			Username:           username,
+			IdentityProvider:   idp.Name(),
+			IdentityProviderID: idp.ID(),
+			Group:              group,
+			MemberOfGroup:      user != nil,
+			APIURL:             cluster.API().URL(),
+		})
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	membership := "No"
+	if user != nil {
+		membership = "Yes"
+	}
+
+	fmt.Printf(""+
+		"Username:                   %s\n"+
+		"Identity Provider:          %s\n"+
+		"Identity Provider ID:       %s\n"+
+		"Member of %-17s %s\n"+
+		"\n",
+		username,
+		idp.Name(),
+		idp.ID(),
+		group+":",
+		membership,
+	)
+
+	if user == nil {
+		reporter.Warnf("User '%s' is not a member of the %s group. To add it back run the following command:\n"+
+			"   rosa edit admin -c %s --rotate-password", username, group, clusterKey)
+	}
+	reporter.Infof("To login, run the following command:\n"+
+		"   oc login %s --username %s", cluster.API().URL(), username)
+	if user != nil {
+		reporter.Infof("To rotate the password of the admin, run the following command:\n"+
+			"   rosa edit admin -c %s --rotate-password", clusterKey)
+	}
 }
@@ -42,14 +42,21 @@ const (
 
 var args struct {
 	clusterKey string
+	health     bool
 }
 
 var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Show details of a cluster",
-	Long:  "Show details of a cluster",
+	Long: "Show details of a cluster.\n\n" +
+		"With '--health' the command also shows the degraded cluster operators, firing alerts and " +
+		"nodes of the cluster, and exits with code 0 when the cluster is healthy, 2 when it is " +
+		"unhealthy and 3 when its health can't be determined.",
 	Example: `  # Describe a cluster named "mycluster"
-  rosa describe cluster --cluster=mycluster`,
+  rosa describe cluster --cluster=mycluster
+
+  # Check the health of a cluster named "mycluster" from a monitoring script
+  rosa describe cluster --cluster=mycluster --health`,
 	Run: run,
 }
 
@@ -67,6 +74,13 @@ func init() {
 		"Name or ID of the cluster to describe.",
 	)
 	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVar(
+		&args.health,
+		"health",
+		false,
+		"Show the health of the cluster and exit with a non-zero code if it isn't healthy.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -137,8 +151,21 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	var str string
+	var health *clusterHealth
+	healthExitCode := healthyExitCode
+	if args.health {
+		health, healthExitCode = getHealth(reporter, ocmClient, cluster, clusterKey)
+	}
+
 	if output.HasFlag() {
+		if health != nil {
+			err = output.Print(health)
+			if err != nil {
+				reporter.Errorf("%s", err)
+				os.Exit(1)
+			}
+			os.Exit(healthExitCode)
+		}
 		err = output.Print(cluster)
 		if err != nil {
 			reporter.Errorf("%s", err)
@@ -147,11 +174,30 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(0)
 	}
 
-	creatorARN, err := arn.Parse(cluster.Properties()[properties.CreatorARN])
+	str, err := Format(ocmClient, cluster)
 	if err != nil {
-		reporter.Errorf("Failed to parse creator ARN for cluster '%s'", clusterKey)
+		reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
+	if health != nil {
+		str = fmt.Sprintf("%s%s", str, formatHealth(health))
+	}
+	// Print short cluster description:
+	fmt.Print(str)
+	fmt.Println()
+
+	if health != nil {
+		os.Exit(healthExitCode)
+	}
+}
+
+// Format returns the description of the cluster that is printed by the command when no output
+// format is given.
+func Format(ocmClient *ocm.Client, cluster *cmv1.Cluster) (string, error) {
+	creatorARN, err := arn.Parse(cluster.Properties()[properties.CreatorARN])
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse creator ARN for cluster '%s'", cluster.ID())
+	}
 	phase := ""
 
 	if cluster.State() == cmv1.ClusterStatePending {
@@ -187,8 +233,7 @@ func run(cmd *cobra.Command, argv []string) {
 
 	scheduledUpgrade, upgradeState, err := ocmClient.GetScheduledUpgrade(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return "", fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", cluster.ID(), err)
 	}
 
 	detailsPage := getDetailsLink(ocmClient.GetConnectionURL())
@@ -199,8 +244,7 @@ func run(cmd *cobra.Command, argv []string) {
 	var nodesStr string
 	machinePools, err := ocmClient.GetMachinePools(cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return "", fmt.Errorf("Failed to get machine pools for cluster '%s': %v", cluster.ID(), err)
 	}
 	// Accumulate all replicas across machine pools
 	for _, machinePool := range machinePools {
@@ -244,7 +288,7 @@ func run(cmd *cobra.Command, argv []string) {
 	}
 
 	// Print short cluster description:
-	str = fmt.Sprintf(""+
+	str := fmt.Sprintf(""+
 		"Name:                       %s\n"+
 		"ID:                         %s\n"+
 		"External ID:                %s\n"+
@@ -354,9 +398,7 @@ func run(cmd *cobra.Command, argv []string) {
 			cluster.Status().ProvisionErrorMessage(),
 		)
 	}
-	// Print short cluster description:
-	fmt.Print(str)
-	fmt.Println()
+	return str, nil
 }
 
 func getDetailsLink(environment string) string {
@@ -0,0 +1,7 @@
+package cluster
+
+// Unexported types and functions used by the tests of the package
+type ClusterHealth = clusterHealth
+type OperatorHealth = operatorHealth
+
+var GetHealthExitCode = getHealthExitCode
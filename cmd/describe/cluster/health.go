@@ -0,0 +1,210 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Exit codes used with '--health' so that the command can be used by monitoring scripts
+const (
+	healthyExitCode   = 0
+	unhealthyExitCode = 2
+	unknownExitCode   = 3
+)
+
+type operatorHealth struct {
+	Name      string `json:"name"`
+	Condition string `json:"condition"`
+	Reason    string `json:"reason,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+type clusterHealth struct {
+	State                 string              `json:"state"`
+	DegradedOperators     []*operatorHealth   `json:"degraded_operators"`
+	FiringAlerts          map[string][]string `json:"firing_alerts"`
+	Nodes                 map[string]int      `json:"nodes"`
+	ProvisionErrorCode    string              `json:"provision_error_code,omitempty"`
+	ProvisionErrorMessage string              `json:"provision_error_message,omitempty"`
+	ProvisionErrorHint    string              `json:"provision_error_hint,omitempty"`
+}
+
+// getHealth collects the health of the cluster from its status and metrics, and returns it
+// together with the exit code that summarizes it.
+func getHealth(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster,
+	clusterKey string) (*clusterHealth, int) {
+	health := &clusterHealth{
+		State:             string(cluster.HealthState()),
+		DegradedOperators: []*operatorHealth{},
+		FiringAlerts:      map[string][]string{},
+		Nodes:             map[string]int{},
+	}
+	if health.State == "" {
+		health.State = string(cmv1.ClusterHealthStateUnknown)
+	}
+
+	if cluster.Status().ProvisionErrorCode() != "" || cluster.Status().ProvisionErrorMessage() != "" {
+		health.ProvisionErrorCode = cluster.Status().ProvisionErrorCode()
+		health.ProvisionErrorMessage = cluster.Status().ProvisionErrorMessage()
+		health.ProvisionErrorHint = ocm.GetProvisionErrorHint(clusterKey,
+			health.ProvisionErrorCode, health.ProvisionErrorMessage)
+	}
+	// Metrics are only reported once the cluster is ready
+	if cluster.State() != cmv1.ClusterStateReady {
+		return health, getHealthExitCode(cluster, health, false)
+	}
+
+	metricsAvailable := true
+	reporter.Debugf("Loading cluster operators for cluster '%s'", clusterKey)
+	operators, err := ocmClient.GetClusterOperators(cluster.ID())
+	if err != nil {
+		reporter.Warnf("Failed to get cluster operators for cluster '%s': %v", clusterKey, err)
+		metricsAvailable = false
+	}
+	for _, operator := range operators {
+		if operator.Condition() == cmv1.ClusterOperatorStateDegraded ||
+			operator.Condition() == cmv1.ClusterOperatorStateFailing {
+			health.DegradedOperators = append(health.DegradedOperators, &operatorHealth{
+				Name:      operator.Name(),
+				Condition: string(operator.Condition()),
+				Reason:    operator.Reason(),
+				Version:   operator.Version(),
+			})
+		}
+	}
+	sort.Slice(health.DegradedOperators, func(i, j int) bool {
+		return health.DegradedOperators[i].Name < health.DegradedOperators[j].Name
+	})
+
+	reporter.Debugf("Loading alerts for cluster '%s'", clusterKey)
+	alerts, err := ocmClient.GetAlerts(cluster.ID())
+	if err != nil {
+		reporter.Warnf("Failed to get alerts for cluster '%s': %v", clusterKey, err)
+		metricsAvailable = false
+	}
+	for _, alert := range alerts {
+		severity := string(alert.Severity())
+		health.FiringAlerts[severity] = append(health.FiringAlerts[severity], alert.Name())
+	}
+	for severity := range health.FiringAlerts {
+		sort.Strings(health.FiringAlerts[severity])
+	}
+
+	reporter.Debugf("Loading nodes for cluster '%s'", clusterKey)
+	nodes, err := ocmClient.GetNodes(cluster.ID())
+	if err != nil {
+		reporter.Warnf("Failed to get nodes for cluster '%s': %v", clusterKey, err)
+		metricsAvailable = false
+	}
+	for _, node := range nodes {
+		health.Nodes[string(node.Type())] += node.Amount()
+	}
+
+	return health, getHealthExitCode(cluster, health, metricsAvailable)
+}
+
+// getHealthExitCode returns the exit code that summarizes the health of the cluster. Clusters in
+// error state are unhealthy, and the health of clusters that aren't ready or whose metrics
+// couldn't be loaded is unknown unless the loaded metrics already show a problem.
+func getHealthExitCode(cluster *cmv1.Cluster, health *clusterHealth, metricsAvailable bool) int {
+	switch {
+	case cluster.State() == cmv1.ClusterStateError:
+		return unhealthyExitCode
+	case cluster.State() != cmv1.ClusterStateReady:
+		return unknownExitCode
+	case cluster.HealthState() == cmv1.ClusterHealthStateUnhealthy,
+		len(health.DegradedOperators) > 0,
+		len(health.FiringAlerts[string(cmv1.AlertSeverityCritical)]) > 0:
+		return unhealthyExitCode
+	case !metricsAvailable:
+		return unknownExitCode
+	}
+	return healthyExitCode
+}
+
+func formatHealth(health *clusterHealth) string {
+	str := fmt.Sprintf(""+
+		"Health:\n"+
+		" - State:                   %s\n", health.State)
+
+	str = fmt.Sprintf("%s - Nodes:", str)
+	if len(health.Nodes) == 0 {
+		str = fmt.Sprintf("%s                   N/A\n", str)
+	} else {
+		str = fmt.Sprintf("%s\n", str)
+		for _, role := range []cmv1.NodeType{cmv1.NodeTypeMaster, cmv1.NodeTypeInfra, cmv1.NodeTypeCompute} {
+			if amount, ok := health.Nodes[string(role)]; ok {
+				str = fmt.Sprintf("%s    - %-22s%d\n", str, strings.Title(string(role))+":", amount)
+			}
+		}
+	}
+
+	str = fmt.Sprintf("%s - Degraded Operators:", str)
+	if len(health.DegradedOperators) == 0 {
+		str = fmt.Sprintf("%s      None\n", str)
+	} else {
+		str = fmt.Sprintf("%s\n", str)
+		for _, operator := range health.DegradedOperators {
+			reason := ""
+			if operator.Reason != "" {
+				reason = ": " + operator.Reason
+			}
+			str = fmt.Sprintf("%s    - %s (%s%s)\n", str, operator.Name, operator.Condition, reason)
+		}
+	}
+
+	str = fmt.Sprintf("%s - Firing Alerts:", str)
+	if len(health.FiringAlerts) == 0 {
+		str = fmt.Sprintf("%s           None\n", str)
+	} else {
+		str = fmt.Sprintf("%s\n", str)
+		severities := []string{
+			string(cmv1.AlertSeverityCritical),
+			string(cmv1.AlertSeverityWarning),
+			string(cmv1.AlertSeverityNone),
+		}
+		for _, severity := range severities {
+			names := health.FiringAlerts[severity]
+			if len(names) == 0 {
+				continue
+			}
+			str = fmt.Sprintf("%s    - %-22s%d (%s)\n", str, strings.Title(severity)+":",
+				len(names), strings.Join(names, ", "))
+		}
+	}
+
+	if health.ProvisionErrorCode != "" || health.ProvisionErrorMessage != "" {
+		str = fmt.Sprintf("%s"+
+			" - Provision Error Code:    %s\n"+
+			" - Provision Error Message: %s\n"+
+			" - Remediation:             %s\n",
+			str,
+			health.ProvisionErrorCode,
+			health.ProvisionErrorMessage,
+			health.ProvisionErrorHint,
+		)
+	}
+	return str
+}
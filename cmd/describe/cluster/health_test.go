@@ -0,0 +1,48 @@
+package cluster_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/cmd/describe/cluster"
+)
+
+var _ = Describe("Health exit code", func() {
+	DescribeTable("summarizes the health of the cluster",
+		func(state cmv1.ClusterState, healthState cmv1.ClusterHealthState, health *cluster.ClusterHealth,
+			metricsAvailable bool, expected int) {
+			item, err := cmv1.NewCluster().State(state).HealthState(healthState).Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.GetHealthExitCode(item, health, metricsAvailable)).To(Equal(expected))
+		},
+		Entry("healthy",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateHealthy, &cluster.ClusterHealth{}, true, 0),
+		Entry("warning alerts",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateHealthy, &cluster.ClusterHealth{
+				FiringAlerts: map[string][]string{"warning": {"KubePodNotReady"}},
+			}, true, 0),
+		Entry("error state",
+			cmv1.ClusterStateError, cmv1.ClusterHealthStateUnknown, &cluster.ClusterHealth{}, false, 2),
+		Entry("unhealthy state",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateUnhealthy, &cluster.ClusterHealth{}, true, 2),
+		Entry("degraded operators",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateHealthy, &cluster.ClusterHealth{
+				DegradedOperators: []*cluster.OperatorHealth{{Name: "ingress", Condition: "degraded"}},
+			}, true, 2),
+		Entry("critical alerts",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateHealthy, &cluster.ClusterHealth{
+				FiringAlerts: map[string][]string{"critical": {"etcdNoLeader"}},
+			}, true, 2),
+		Entry("critical alerts with missing metrics",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateHealthy, &cluster.ClusterHealth{
+				FiringAlerts: map[string][]string{"critical": {"etcdNoLeader"}},
+			}, false, 2),
+		Entry("installing",
+			cmv1.ClusterStateInstalling, cmv1.ClusterHealthStateUnknown, &cluster.ClusterHealth{}, false, 3),
+		Entry("missing metrics",
+			cmv1.ClusterStateReady, cmv1.ClusterHealthStateHealthy, &cluster.ClusterHealth{}, false, 3),
+	)
+})
@@ -22,6 +22,9 @@ import (
 	"github.com/openshift/rosa/cmd/describe/addon"
 	"github.com/openshift/rosa/cmd/describe/admin"
 	"github.com/openshift/rosa/cmd/describe/cluster"
+	"github.com/openshift/rosa/cmd/describe/idp"
+	"github.com/openshift/rosa/cmd/describe/machinepool"
+	"github.com/openshift/rosa/cmd/describe/upgrade"
 	"github.com/openshift/rosa/pkg/arguments"
 )
 
@@ -35,6 +38,9 @@ func init() {
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(admin.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(idp.Cmd)
+	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(upgrade.Cmd)
 
 	flags := Cmd.PersistentFlags()
 	arguments.AddProfileFlag(flags)
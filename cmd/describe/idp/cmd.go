@@ -0,0 +1,328 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Value printed instead of secrets
+const redacted = "<redacted>"
+
+// Attributes of the identity provider types that contain secrets
+var secretAttributes = map[string]bool{
+	"client_secret": true,
+	"bind_password": true,
+	"password":      true,
+}
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "idp NAME",
+	Short: "Show details of an identity provider",
+	Long: "Show the configuration of an identity provider of a cluster, together with the OAuth " +
+		"callback URL to register with the provider. Secrets are redacted.",
+	Example: `  # Describe the identity provider named "github-1" on a cluster named "mycluster"
+  rosa describe idp github-1 --cluster=mycluster`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf(
+				"Expected exactly one command line parameter containing the name of the identity provider",
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster that the identity provider belongs to (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	output.AddFlag(Cmd)
+}
+
+func run(_ *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	idpName := argv[0]
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	// Try to find the identity provider:
+	reporter.Debugf("Loading identity provider '%s'", idpName)
+	idps, err := ocmClient.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	var idp *cmv1.IdentityProvider
+	for _, item := range idps {
+		if item.Name() == idpName {
+			idp = item
+		}
+	}
+	if idp == nil {
+		reporter.Errorf("Failed to get identity provider '%s' for cluster '%s'", idpName, clusterKey)
+		os.Exit(1)
+	}
+
+	if output.HasFlag() {
+		body, err := redact(idp)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		err = output.Print(body)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	idpType := ocm.IdentityProviderType(idp)
+	str := fmt.Sprintf(""+
+		"Name:                       %s\n"+
+		"ID:                         %s\n"+
+		"Type:                       %s\n"+
+		"Mapping Method:             %s\n",
+		idp.Name(),
+		idp.ID(),
+		idpType,
+		idp.MappingMethod(),
+	)
+
+	// Providers that log users in through a redirect need the callback URL registered
+	switch idpType {
+	case "GitHub", "GitLab", "Google", "OpenID":
+		str = fmt.Sprintf("%s"+
+			"Callback URL:               %s\n", str,
+			ocm.GetOAuthCallbackURL(cluster, idp.Name()))
+	}
+
+	switch idpType {
+	case "GitHub":
+		github := idp.Github()
+		str = fmt.Sprintf("%s"+
+			"Client ID:                  %s\n"+
+			"Client Secret:              %s\n"+
+			"Hostname:                   %s\n"+
+			"Organizations:              %s\n"+
+			"Teams:                      %s\n"+
+			"CA:                         %s\n", str,
+			github.ClientID(),
+			redacted,
+			valueOrNone(github.Hostname()),
+			valueOrNone(strings.Join(github.Organizations(), ", ")),
+			valueOrNone(strings.Join(github.Teams(), ", ")),
+			configured(github.CA()),
+		)
+	case "GitLab":
+		gitlab := idp.Gitlab()
+		str = fmt.Sprintf("%s"+
+			"Client ID:                  %s\n"+
+			"Client Secret:              %s\n"+
+			"URL:                        %s\n"+
+			"CA:                         %s\n", str,
+			gitlab.ClientID(),
+			redacted,
+			gitlab.URL(),
+			configured(gitlab.CA()),
+		)
+	case "Google":
+		google := idp.Google()
+		str = fmt.Sprintf("%s"+
+			"Client ID:                  %s\n"+
+			"Client Secret:              %s\n"+
+			"Hosted Domain:              %s\n", str,
+			google.ClientID(),
+			redacted,
+			valueOrNone(google.HostedDomain()),
+		)
+	case "htpasswd":
+		str = fmt.Sprintf("%s"+
+			"Username:                   %s\n"+
+			"Password:                   %s\n", str,
+			idp.Htpasswd().Username(),
+			redacted,
+		)
+	case "LDAP":
+		ldap := idp.LDAP()
+		bindPassword := "None"
+		if ldap.BindDN() != "" {
+			bindPassword = redacted
+		}
+		str = fmt.Sprintf("%s"+
+			"URL:                        %s\n"+
+			"Insecure:                   %t\n"+
+			"Bind DN:                    %s\n"+
+			"Bind Password:              %s\n"+
+			"CA:                         %s\n"+
+			"Attributes:\n"+
+			" - ID:                      %s\n"+
+			" - Preferred Username:      %s\n"+
+			" - Name:                    %s\n"+
+			" - Email:                   %s\n", str,
+			ldap.URL(),
+			ldap.Insecure(),
+			valueOrNone(ldap.BindDN()),
+			bindPassword,
+			configured(ldap.CA()),
+			valueOrNone(strings.Join(ldap.Attributes().ID(), ", ")),
+			valueOrNone(strings.Join(ldap.Attributes().PreferredUsername(), ", ")),
+			valueOrNone(strings.Join(ldap.Attributes().Name(), ", ")),
+			valueOrNone(strings.Join(ldap.Attributes().Email(), ", ")),
+		)
+	case "OpenID":
+		openID := idp.OpenID()
+		str = fmt.Sprintf("%s"+
+			"Client ID:                  %s\n"+
+			"Client Secret:              %s\n"+
+			"Issuer URL:                 %s\n"+
+			"Extra Scopes:               %s\n"+
+			"CA:                         %s\n"+
+			"Claims:\n"+
+			" - Email:                   %s\n"+
+			" - Name:                    %s\n"+
+			" - Preferred Username:      %s\n", str,
+			openID.ClientID(),
+			redacted,
+			openID.Issuer(),
+			valueOrNone(strings.Join(openID.ExtraScopes(), ", ")),
+			configured(openID.CA()),
+			valueOrNone(strings.Join(openID.Claims().Email(), ", ")),
+			valueOrNone(strings.Join(openID.Claims().Name(), ", ")),
+			valueOrNone(strings.Join(openID.Claims().PreferredUsername(), ", ")),
+		)
+	}
+
+	fmt.Print(str)
+	fmt.Println()
+}
+
+// redact returns the JSON representation of the identity provider with the secrets replaced.
+func redact(idp *cmv1.IdentityProvider) (map[string]interface{}, error) {
+	var b bytes.Buffer
+	err := cmv1.MarshalIdentityProvider(idp, &b)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &body)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range body {
+		if config, ok := value.(map[string]interface{}); ok {
+			for key := range config {
+				if secretAttributes[key] {
+					config[key] = redacted
+				}
+			}
+		}
+	}
+	return body, nil
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "None"
+	}
+	return value
+}
+
+func configured(ca string) string {
+	if ca == "" {
+		return "None"
+	}
+	return "Configured"
+}
@@ -0,0 +1,296 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+var args struct {
+	clusterKey string
+}
+
+// machinePoolDescription is the representation of the machine pool printed with '--output'
+type machinePoolDescription struct {
+	MachinePool  json.RawMessage `json:"machine_pool"`
+	InstanceType json.RawMessage `json:"instance_type,omitempty"`
+}
+
+var Cmd = &cobra.Command{
+	Use:     "machinepool ID",
+	Aliases: []string{"machine-pool"},
+	Short:   "Show details of a machine pool",
+	Long:    "Show details of a machine pool on a cluster, including the resources of its instance type.",
+	Example: `  # Describe the machine pool with ID "mp-1" on a cluster named "mycluster"
+  rosa describe machinepool mp-1 --cluster=mycluster
+
+  # Describe the default machine pool of a cluster named "mycluster"
+  rosa describe machinepool Default --cluster=mycluster`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf(
+				"Expected exactly one command line parameter containing the id of the machine pool",
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster that the machine pool belongs to (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	output.AddFlag(Cmd)
+}
+
+func run(_ *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	machinePoolID := argv[0]
+	if machinePoolID != "Default" && !machinePoolKeyRE.MatchString(machinePoolID) {
+		reporter.Errorf("Expected a valid identifier for the machine pool")
+		os.Exit(1)
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	// Try to find the machine pool:
+	var machinePool *cmv1.MachinePool
+	if machinePoolID == "Default" {
+		machinePool = ocm.DefaultMachinePool(cluster)
+	} else {
+		reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
+		machinePools, err := ocmClient.GetMachinePools(cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		for _, item := range machinePools {
+			if item.ID() == machinePoolID {
+				machinePool = item
+			}
+		}
+		if machinePool == nil {
+			reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
+			os.Exit(1)
+		}
+	}
+
+	// Load the resources of the instance type:
+	reporter.Debugf("Loading instance type '%s'", machinePool.InstanceType())
+	machineTypes, err := ocmClient.GetAvailableMachineTypes()
+	if err != nil {
+		reporter.Errorf("Failed to get instance types: %v", err)
+		os.Exit(1)
+	}
+	var machineType *cmv1.MachineType
+	for _, item := range machineTypes {
+		if item.MachineType.ID() == machinePool.InstanceType() {
+			machineType = item.MachineType
+		}
+	}
+
+	if output.HasFlag() {
+		err = printMachinePool(machinePool, machineType)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	cpu := "Unknown"
+	memory := "Unknown"
+	if machineType != nil {
+		cpu = fmt.Sprintf("%d", int(machineType.CPU().Value()))
+		memory = ocm.ByteCountIEC(int(machineType.Memory().Value()), machineType.Memory().Unit())
+	}
+
+	str := fmt.Sprintf(""+
+		"ID:                         %s\n"+
+		"Cluster:                    %s\n"+
+		"Instance Type:              %s\n"+
+		"CPU Cores:                  %s\n"+
+		"Memory:                     %s\n",
+		machinePool.ID(),
+		cluster.Name(),
+		machinePool.InstanceType(),
+		cpu,
+		memory,
+	)
+	autoscaling := machinePool.Autoscaling()
+	if autoscaling != nil {
+		str = fmt.Sprintf("%s"+
+			"Autoscaling:                Yes\n"+
+			"Min Replicas:               %d\n"+
+			"Max Replicas:               %d\n", str,
+			autoscaling.MinReplicas(),
+			autoscaling.MaxReplicas(),
+		)
+	} else {
+		str = fmt.Sprintf("%s"+
+			"Autoscaling:                No\n"+
+			"Replicas:                   %d\n", str,
+			machinePool.Replicas(),
+		)
+	}
+	str = fmt.Sprintf("%s"+
+		"Availability Zones:         %s\n"+
+		"Labels:                     %s\n"+
+		"Taints:                     %s\n", str,
+		valueOrNone(strings.Join(machinePool.AvailabilityZones(), ", ")),
+		printLabels(machinePool.Labels()),
+		printTaints(machinePool.Taints()),
+	)
+
+	fmt.Print(str)
+	fmt.Println()
+}
+
+func printMachinePool(machinePool *cmv1.MachinePool, machineType *cmv1.MachineType) error {
+	var machinePoolJSON bytes.Buffer
+	err := cmv1.MarshalMachinePool(machinePool, &machinePoolJSON)
+	if err != nil {
+		return err
+	}
+	description := &machinePoolDescription{
+		MachinePool: machinePoolJSON.Bytes(),
+	}
+	if machineType != nil {
+		var machineTypeJSON bytes.Buffer
+		err = cmv1.MarshalMachineType(machineType, &machineTypeJSON)
+		if err != nil {
+			return err
+		}
+		description.InstanceType = machineTypeJSON.Bytes()
+	}
+	return output.Print(description)
+}
+
+func printLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "None"
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	output := []string{}
+	for _, key := range keys {
+		output = append(output, fmt.Sprintf("\n - %s=%s", key, labels[key]))
+	}
+	return strings.Join(output, "")
+}
+
+func printTaints(taints []*cmv1.Taint) string {
+	if len(taints) == 0 {
+		return "None"
+	}
+	output := []string{}
+	for _, taint := range taints {
+		output = append(output, fmt.Sprintf("\n - %s=%s (%s)", taint.Key(), taint.Value(), taint.Effect()))
+	}
+	return strings.Join(output, "")
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "None"
+	}
+	return value
+}
@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Time to wait between checks of the upgrade state while watching
+const watchInterval = 30 * time.Second
+
+var args struct {
+	clusterKey string
+	watch      bool
+}
+
+// upgradeDescription is the representation of the scheduled upgrade printed with '--output'
+type upgradeDescription struct {
+	UpgradePolicy        json.RawMessage `json:"upgrade_policy"`
+	State                json.RawMessage `json:"state"`
+	NodeDrainGracePeriod string          `json:"node_drain_grace_period,omitempty"`
+}
+
+var Cmd = &cobra.Command{
+	Use:     "upgrade",
+	Aliases: []string{"upgrades"},
+	Short:   "Show details of a scheduled cluster upgrade",
+	Long:    "Show details of the upgrade scheduled for a cluster and optionally watch its progress",
+	Example: `  # Describe the upgrade scheduled for a cluster named "mycluster"
+  rosa describe upgrade -c mycluster
+
+  # Watch the scheduled upgrade until it completes
+  rosa describe upgrade -c mycluster --watch`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to describe the upgrade of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVarP(
+		&args.watch,
+		"watch",
+		"w",
+		false,
+		"Watch the upgrade until it completes, fails or is cancelled. "+
+			"Exits with a non-zero code if the upgrade fails.",
+	)
+
+	output.AddFlag(Cmd)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	reporter.Debugf("Loading scheduled upgrade for cluster '%s'", clusterKey)
+	scheduledUpgrade, upgradeState, err := ocmClient.GetScheduledUpgrade(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	if scheduledUpgrade == nil {
+		reporter.Warnf("There are no scheduled upgrades on cluster '%s'", clusterKey)
+		os.Exit(0)
+	}
+
+	nodeDrainGracePeriod := ocm.GetNodeDrainGracePeriod(cluster)
+	if output.HasFlag() {
+		err = printUpgrade(scheduledUpgrade, upgradeState, nodeDrainGracePeriod)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if nodeDrainGracePeriod == "" {
+		nodeDrainGracePeriod = "N/A"
+	}
+
+	fmt.Printf(""+
+		"ID:                         %s\n"+
+		"Cluster ID:                 %s\n"+
+		"Current Version:            %s\n"+
+		"Version:                    %s\n"+
+		"Schedule Type:              %s\n"+
+		"Next Run:                   %s\n"+
+		"State:                      %s\n"+
+		"State Description:          %s\n"+
+		"Node Drain Grace Period:    %s\n",
+		scheduledUpgrade.ID(),
+		cluster.ID(),
+		cluster.OpenshiftVersion(),
+		scheduledUpgrade.Version(),
+		scheduledUpgrade.ScheduleType(),
+		scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"),
+		upgradeState.Value(),
+		upgradeState.Description(),
+		nodeDrainGracePeriod,
+	)
+	fmt.Println()
+
+	if !args.watch {
+		return
+	}
+
+	// Poll the upgrade state until it reaches a final state:
+	version := scheduledUpgrade.Version()
+	watch := ocmClient.WatchUpgrade(cluster.ID(), version)
+	for {
+		switch upgradeState.Value() {
+		case cmv1.UpgradePolicyStateValueCompleted:
+			reporter.Infof("Cluster '%s' has been upgraded to version %s", clusterKey, version)
+			os.Exit(0)
+		case cmv1.UpgradePolicyStateValueFailed:
+			reporter.Errorf("Upgrade of cluster '%s' to version %s failed: %s",
+				clusterKey, version, upgradeState.Description())
+			os.Exit(1)
+		case cmv1.UpgradePolicyStateValueCancelled:
+			reporter.Warnf("Upgrade of cluster '%s' to version %s was cancelled", clusterKey, version)
+			os.Exit(1)
+		}
+
+		time.Sleep(watchInterval)
+
+		lastState := upgradeState.Value()
+		upgradeState, err = watch.State()
+		if err != nil {
+			reporter.Errorf("Failed to get upgrade state for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		if upgradeState.Value() != lastState {
+			reporter.Infof("Upgrade of cluster '%s' to version %s is %s: %s",
+				clusterKey, version, upgradeState.Value(), upgradeState.Description())
+		}
+	}
+}
+
+func printUpgrade(upgradePolicy *cmv1.UpgradePolicy, upgradeState *cmv1.UpgradePolicyState,
+	nodeDrainGracePeriod string) error {
+	var policyJSON, stateJSON bytes.Buffer
+	err := cmv1.MarshalUpgradePolicy(upgradePolicy, &policyJSON)
+	if err != nil {
+		return err
+	}
+	err = cmv1.MarshalUpgradePolicyState(upgradeState, &stateJSON)
+	if err != nil {
+		return err
+	}
+	return output.Print(&upgradeDescription{
+		UpgradePolicy:        policyJSON.Bytes(),
+		State:                stateJSON.Bytes(),
+		NodeDrainGracePeriod: nodeDrainGracePeriod,
+	})
+}
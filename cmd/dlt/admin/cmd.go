@@ -129,7 +129,7 @@ func run(cmd *cobra.Command, _ []string) {
 
 	var idp *cmv1.IdentityProvider
 	for _, item := range idps {
-		if ocm.IdentityProviderType(item) == "htpasswd" {
+		if ocm.IdentityProviderType(item) == "htpasswd" && item.Name() == idpName {
 			idp = item
 		}
 	}
@@ -0,0 +1,261 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+const (
+	idpName = "Cluster-Admin"
+	group   = "cluster-admins"
+)
+
+var args struct {
+	clusterKey     string
+	rotatePassword bool
+	passwordStdin  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Edit the cluster-admin user",
+	Long:  "Edit the cluster-admin user created with 'rosa create admin', for example to rotate its password",
+	Example: `  # Rotate the password of the admin of a cluster named "mycluster" to a new random password
+  rosa edit admin -c mycluster --rotate-password
+
+  # Rotate the password to one stored in a vault
+  vault kv get -field=password secret/mycluster | rosa edit admin -c mycluster --rotate-password --password-stdin -y`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster that cluster-admin belongs to (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVar(
+		&args.rotatePassword,
+		"rotate-password",
+		false,
+		"Replace the password of the admin user with a new auto-generated password.",
+	)
+
+	flags.BoolVar(
+		&args.passwordStdin,
+		"password-stdin",
+		false,
+		"Read the new password from standard input instead of generating it. Requires '--yes'.",
+	)
+
+	confirm.AddFlag(flags)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	if !args.rotatePassword {
+		reporter.Errorf("Nothing to edit. Use '--rotate-password' to rotate the password of the admin")
+		os.Exit(1)
+	}
+
+	// Read the password before any prompt so that it doesn't compete with the confirmation
+	var password string
+	if args.passwordStdin {
+		if !confirm.Yes() {
+			reporter.Errorf("Option '--password-stdin' requires '--yes', as standard input can't be used to confirm")
+			os.Exit(1)
+		}
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			reporter.Errorf("Failed to read password from standard input: %v", err)
+			os.Exit(1)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+		if password == "" {
+			reporter.Errorf("Expected a password in standard input")
+			os.Exit(1)
+		}
+		if strings.ContainsAny(password, " \t\r\n") {
+			reporter.Errorf("Password must not contain whitespace")
+			os.Exit(1)
+		}
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	// Try to find the htpasswd identity provider:
+	reporter.Debugf("Loading '%s' identity provider", idpName)
+	idps, err := ocmClient.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v", idpName, clusterKey, err)
+		os.Exit(1)
+	}
+
+	var idp *cmv1.IdentityProvider
+	for _, item := range idps {
+		if ocm.IdentityProviderType(item) == "htpasswd" && item.Name() == idpName {
+			idp = item
+		}
+	}
+	if idp == nil || idp.Htpasswd() == nil {
+		reporter.Errorf("There is no admin on cluster '%s'. To create it run the following command:\n"+
+			"   rosa create admin -c %s", clusterKey, clusterKey)
+		os.Exit(1)
+	}
+	username := idp.Htpasswd().Username()
+
+	if !confirm.Confirm("rotate the password of %s user on cluster %s", username, clusterKey) {
+		os.Exit(0)
+	}
+
+	generated := password == ""
+	if generated {
+		password, err = ocm.GenerateRandomPassword(23)
+		if err != nil {
+			reporter.Errorf("Failed to generate a random password")
+			os.Exit(1)
+		}
+	}
+
+	// Replace the credentials of the existing identity provider so that the identities already
+	// mapped to it keep working:
+	reporter.Debugf("Updating '%s' identity provider on cluster '%s'", idp.Name(), clusterKey)
+	update, err := cmv1.NewIdentityProvider().
+		ID(idp.ID()).
+		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(idp.Name()).
+		MappingMethod(idp.MappingMethod()).
+		Htpasswd(
+			cmv1.NewHTPasswdIdentityProvider().
+				Username(username).
+				Password(password),
+		).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to update '%s' identity provider for cluster '%s'", idp.Name(), clusterKey)
+		os.Exit(1)
+	}
+	_, err = ocmClient.UpdateIdentityProvider(cluster.ID(), update)
+	if err != nil {
+		reporter.Errorf("Failed to update '%s' identity provider for cluster '%s': %v",
+			idp.Name(), clusterKey, err)
+		os.Exit(1)
+	}
+
+	// Make sure that the admin is still a member of the cluster-admins group:
+	user, err := ocmClient.GetUser(cluster.ID(), group, username)
+	if err != nil {
+		reporter.Errorf("Failed to get '%s' user for cluster '%s': %v", username, clusterKey, err)
+		os.Exit(1)
+	}
+	if user == nil {
+		reporter.Debugf("Adding '%s' user to %s group on cluster '%s'", username, group, clusterKey)
+		user, err = cmv1.NewUser().ID(username).Build()
+		if err != nil {
+			reporter.Errorf("Failed to create user '%s' for cluster '%s'", username, clusterKey)
+			os.Exit(1)
+		}
+		_, err = ocmClient.CreateUser(cluster.ID(), group, user)
+		if err != nil {
+			reporter.Errorf("Failed to add user '%s' to cluster '%s': %v", username, clusterKey, err)
+			os.Exit(1)
+		}
+	}
+
+	reporter.Infof("Password of admin user '%s' has been rotated on cluster '%s'.", username, clusterKey)
+	if generated {
+		reporter.Infof("Please securely store this generated password. " +
+			"If you lose this password you can rotate it again.")
+		reporter.Infof("To login, run the following command:\n\n"+
+			"   oc login %s --username %s --password %s\n", cluster.API().URL(), username, password)
+	}
+	reporter.Infof("It may take up to a minute for the new password to become active.")
+}
@@ -20,9 +20,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/cmd/edit/addon"
+	"github.com/openshift/rosa/cmd/edit/admin"
 	"github.com/openshift/rosa/cmd/edit/cluster"
+	"github.com/openshift/rosa/cmd/edit/idp"
 	"github.com/openshift/rosa/cmd/edit/ingress"
 	"github.com/openshift/rosa/cmd/edit/machinepool"
+	"github.com/openshift/rosa/cmd/edit/upgrade"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/interactive"
 )
@@ -36,9 +39,12 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.AddCommand(addon.Cmd)
+	Cmd.AddCommand(admin.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(upgrade.Cmd)
 
 	flags := Cmd.PersistentFlags()
 	arguments.AddProfileFlag(flags)
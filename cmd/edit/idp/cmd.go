@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	createIdp "github.com/openshift/rosa/cmd/create/idp"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Name of the identity provider managed with the admin commands
+const adminIDPName = "Cluster-Admin"
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "idp NAME",
+	Short: "Edit identity provider for cluster",
+	Long: "Edit an identity provider in place. Options that aren't given keep their current " +
+		"values, and secrets are only replaced when a new value is given.",
+	Example: `  # Restrict the GitHub identity provider named "github-1" to another organization
+  rosa edit idp github-1 --cluster=mycluster --organizations=myorg
+
+  # Rotate the client secret of the OpenID identity provider named "openid-1"
+  rosa edit idp openid-1 --cluster=mycluster --client-secret=mysecret
+
+  # Edit an identity provider following interactive prompts with the current values as defaults
+  rosa edit idp github-1 --cluster=mycluster --interactive`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf(
+				"Expected exactly one command line parameter containing the name of the identity provider",
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to edit the IdP of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	createIdp.AddFlags(flags)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	idpName := argv[0]
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	// Try to find the identity provider:
+	reporter.Debugf("Loading identity provider '%s'", idpName)
+	idps, err := ocmClient.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	var idp *cmv1.IdentityProvider
+	for _, item := range idps {
+		if item.Name() == idpName {
+			idp = item
+		}
+	}
+	if idp == nil {
+		reporter.Errorf("Failed to get identity provider '%s' for cluster '%s'", idpName, clusterKey)
+		os.Exit(1)
+	}
+	if idp.Name() == adminIDPName {
+		reporter.Errorf("Identity provider '%s' is managed by the admin commands. To rotate the "+
+			"admin password run the following command:\n"+
+			"   rosa edit admin -c %s --rotate-password", idpName, clusterKey)
+		os.Exit(1)
+	}
+
+	if interactive.Enabled() {
+		reporter.Infof("Interactive mode enabled.\n" +
+			"The current values are used as defaults and secrets can be left empty to keep them.")
+	}
+
+	idpType := strings.ToLower(ocm.IdentityProviderType(idp))
+	options := createIdp.SetDefaults(cmd, idp)
+	idpBuilder, err := createIdp.BuildIdentityProvider(cmd, cluster, idpType, idpName, options)
+	if err != nil {
+		reporter.Errorf("Failed to edit IDP '%s' for cluster '%s': %v", idpName, clusterKey, err)
+		os.Exit(1)
+	}
+
+	update, err := idpBuilder.ID(idp.ID()).Build()
+	if err != nil {
+		reporter.Errorf("Failed to edit IDP '%s' for cluster '%s': %v", idpName, clusterKey, err)
+		os.Exit(1)
+	}
+
+	reporter.Debugf("Updating identity provider '%s' on cluster '%s'", idpName, clusterKey)
+	_, err = ocmClient.UpdateIdentityProvider(cluster.ID(), update)
+	if err != nil {
+		reporter.Errorf("Failed to update IDP '%s' on cluster '%s': %v", idpName, clusterKey, err)
+		os.Exit(1)
+	}
+
+	reporter.Infof("Identity provider '%s' has been updated on cluster '%s'.\n"+
+		"   It will take up to 1 minute for this configuration to be enabled.", idpName, clusterKey)
+}
@@ -20,13 +20,13 @@ import (
 	"fmt"
 	"os"
 	"regexp"
-	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
@@ -43,7 +43,11 @@ var args struct {
 	minReplicas        int
 	maxReplicas        int
 	labels             string
+	addLabels          string
+	removeLabels       string
 	taints             string
+	addTaints          string
+	removeTaints       string
 }
 
 var Cmd = &cobra.Command{
@@ -54,7 +58,9 @@ var Cmd = &cobra.Command{
 	Example: `  # Set 4 replicas on machine pool 'mp1' on cluster 'mycluster'
   rosa edit machinepool --replicas=4 --cluster=mycluster mp1
   # Enable autoscaling and Set 3-5 replicas on machine pool 'mp1' on cluster 'mycluster'
-  rosa edit machinepool --enable-autoscaling --min-replicas=3 --max-replicas=5 --cluster=mycluster mp1`,
+  rosa edit machinepool --enable-autoscaling --min-replicas=3 --max-replicas=5 --cluster=mycluster mp1
+  # Add a label and remove a taint on machine pool 'mp1' on cluster 'mycluster', keeping the others
+  rosa edit machinepool --add-labels=tier=gpu --remove-taints=dedicated --cluster=mycluster mp1`,
 	Run: run,
 	Args: func(_ *cobra.Command, argv []string) error {
 		if len(argv) != 1 {
@@ -121,6 +127,40 @@ func init() {
 		"Taints for machine pool. Format should be a comma-separated list of 'key=value:ScheduleType'. "+
 			"This list will overwrite any modifications made to node taints on an ongoing basis.",
 	)
+
+	flags.StringVar(
+		&args.addLabels,
+		"add-labels",
+		"",
+		"Labels to add to the machine pool, keeping the existing ones. Format should be a "+
+			"comma-separated list of 'key=value'. Existing labels with the same key are replaced.",
+	)
+
+	flags.StringVar(
+		&args.removeLabels,
+		"remove-labels",
+		"",
+		"Keys of the labels to remove from the machine pool, as a comma-separated list.",
+	)
+
+	flags.StringVar(
+		&args.addTaints,
+		"add-taints",
+		"",
+		"Taints to add to the machine pool, keeping the existing ones. Format should be a "+
+			"comma-separated list of 'key=value:ScheduleType'. Existing taints with the same key "+
+			"and schedule type are replaced.",
+	)
+
+	flags.StringVar(
+		&args.removeTaints,
+		"remove-taints",
+		"",
+		"Taints to remove from the machine pool, as a comma-separated list of 'key' or "+
+			"'key:ScheduleType'. A key alone removes the taints with that key for all schedule types.",
+	)
+
+	confirm.AddFlag(flags)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -186,11 +226,13 @@ func run(cmd *cobra.Command, argv []string) {
 
 	// Editing the default machine pool is a different process
 	if machinePoolID == "Default" {
-		if cmd.Flags().Changed("labels") {
+		if cmd.Flags().Changed("labels") || cmd.Flags().Changed("add-labels") ||
+			cmd.Flags().Changed("remove-labels") {
 			reporter.Errorf("Labels cannot be updated on the Default machine pool")
 			os.Exit(1)
 		}
-		if cmd.Flags().Changed("taints") {
+		if cmd.Flags().Changed("taints") || cmd.Flags().Changed("add-taints") ||
+			cmd.Flags().Changed("remove-taints") {
 			reporter.Errorf("Taints are not supported on the Default machine pool")
 			os.Exit(1)
 		}
@@ -269,82 +311,55 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	labels := args.labels
-	labelMap := make(map[string]string)
-	if interactive.Enabled() {
-		if labels == "" {
-			for lk, lv := range machinePool.Labels() {
-				if labels != "" {
-					labels += ","
-				}
-				labels += fmt.Sprintf("%s=%s", lk, lv)
-			}
-		}
-		labels, err = interactive.GetString(interactive.Input{
-			Question: "Labels",
-			Help:     cmd.Flags().Lookup("labels").Usage,
-			Default:  labels,
-		})
-		if err != nil {
-			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
-		}
-	}
-	labels = strings.Trim(labels, " ")
-	if labels != "" {
-		for _, label := range strings.Split(labels, ",") {
-			if !strings.Contains(label, "=") {
-				reporter.Errorf("Expected key=value format for labels")
-				os.Exit(1)
-			}
-			tokens := strings.Split(label, "=")
-			labelMap[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
-		}
+	labels, err := getLabels(cmd, machinePool.Labels())
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
+	labelsBefore := ocm.FormatLabels(machinePool.Labels())
+	labelsAfter := ocm.FormatLabels(labels)
 
-	taints := args.taints
-	taintBuilders := []*cmv1.TaintBuilder{}
-	if interactive.Enabled() {
-		if taints == "" {
-			for _, taint := range machinePool.Taints() {
-				if taints != "" {
-					taints += ","
-				}
-				taints += fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect())
-			}
+	taints, err := getTaints(cmd, machinePool.Taints())
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	taintsBefore := ocm.FormatTaints(machinePool.Taints())
+	taintsAfter := ocm.FormatTaints(taints)
+
+	// When the labels and taints are patched or edited interactively show the changes and ask
+	// for confirmation, as removing them affects the workloads that can run on the nodes.
+	// Replacing them with the '--labels' and '--taints' flags doesn't prompt.
+	labelsChanged := !equalItems(labelsBefore, labelsAfter)
+	taintsChanged := !equalItems(taintsBefore, taintsAfter)
+	patched := interactive.Enabled()
+	for _, flag := range []string{"add-labels", "remove-labels", "add-taints", "remove-taints"} {
+		patched = patched || cmd.Flags().Changed(flag)
+	}
+	if patched && (labelsChanged || taintsChanged) {
+		if labelsChanged {
+			printDiff("Labels", labelsBefore, labelsAfter)
 		}
-		taints, err = interactive.GetString(interactive.Input{
-			Question: "Taints",
-			Help:     cmd.Flags().Lookup("taints").Usage,
-			Default:  taints,
-		})
-		if err != nil {
-			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+		if taintsChanged {
+			printDiff("Taints", taintsBefore, taintsAfter)
 		}
-	}
-	taints = strings.Trim(taints, " ")
-	if taints != "" {
-		for _, taint := range strings.Split(taints, ",") {
-			if !strings.Contains(taint, "=") || !strings.Contains(taint, ":") {
-				reporter.Errorf("Expected key=value:scheduleType format for taints")
-				os.Exit(1)
-			}
-			tokens := strings.FieldsFunc(taint, Split)
-			taintBuilders = append(taintBuilders, cmv1.NewTaint().Key(tokens[0]).Value(tokens[1]).Effect(tokens[2]))
+		if !confirm.Confirm("update machine pool '%s' on cluster '%s'", machinePoolID, clusterKey) {
+			os.Exit(0)
 		}
 	}
 
 	mpBuilder := cmv1.NewMachinePool().
 		ID(machinePool.ID())
 
-	// Check either for an explicit flag or interactive mode. Since
-	// interactive will always show both labels and taints we can safely
-	// assume that the value entered is the same as the value desired.
-	if cmd.Flags().Changed("labels") || interactive.Enabled() {
-		mpBuilder = mpBuilder.Labels(labelMap)
+	if labelsChanged {
+		mpBuilder = mpBuilder.Labels(labels)
 	}
-	if cmd.Flags().Changed("taints") || interactive.Enabled() {
+	if taintsChanged {
+		taintBuilders := []*cmv1.TaintBuilder{}
+		for _, taint := range taints {
+			taintBuilders = append(taintBuilders,
+				cmv1.NewTaint().Key(taint.Key()).Value(taint.Value()).Effect(taint.Effect()))
+		}
 		mpBuilder = mpBuilder.Taints(taintBuilders...)
 	}
 
@@ -473,6 +488,125 @@ func getReplicas(cmd *cobra.Command,
 	return
 }
 
-func Split(r rune) bool {
-	return r == '=' || r == ':'
+// getLabels returns the labels that the machine pool should have: either the list given with
+// '--labels', which replaces the existing labels, or the existing labels merged with the ones
+// given with '--add-labels' and '--remove-labels'.
+func getLabels(cmd *cobra.Command, existing map[string]string) (map[string]string, error) {
+	var err error
+	replace := cmd.Flags().Changed("labels")
+	if replace && (cmd.Flags().Changed("add-labels") || cmd.Flags().Changed("remove-labels")) {
+		return nil, fmt.Errorf("The '--labels' flag can't be combined with '--add-labels' or '--remove-labels'")
+	}
+
+	if replace {
+		labels := args.labels
+		if interactive.Enabled() {
+			labels, err = interactive.GetString(interactive.Input{
+				Question: "Labels",
+				Help:     cmd.Flags().Lookup("labels").Usage,
+				Default:  labels,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+			}
+		}
+		return ocm.ParseLabels(labels)
+	}
+
+	addLabels := args.addLabels
+	removeLabels := args.removeLabels
+	if interactive.Enabled() {
+		addLabels, err = interactive.GetString(interactive.Input{
+			Question: "Add labels",
+			Help:     cmd.Flags().Lookup("add-labels").Usage,
+			Default:  addLabels,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+		}
+		removeLabels, err = interactive.GetString(interactive.Input{
+			Question: "Remove labels",
+			Help:     cmd.Flags().Lookup("remove-labels").Usage,
+			Default:  removeLabels,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+		}
+	}
+	add, err := ocm.ParseLabels(addLabels)
+	if err != nil {
+		return nil, err
+	}
+	return ocm.MergeLabels(existing, add, ocm.ParseKeys(removeLabels))
+}
+
+// getTaints returns the taints that the machine pool should have: either the list given with
+// '--taints', which replaces the existing taints, or the existing taints merged with the ones
+// given with '--add-taints' and '--remove-taints'.
+func getTaints(cmd *cobra.Command, existing []*cmv1.Taint) ([]*cmv1.Taint, error) {
+	var err error
+	replace := cmd.Flags().Changed("taints")
+	if replace && (cmd.Flags().Changed("add-taints") || cmd.Flags().Changed("remove-taints")) {
+		return nil, fmt.Errorf("The '--taints' flag can't be combined with '--add-taints' or '--remove-taints'")
+	}
+
+	if replace {
+		taints := args.taints
+		if interactive.Enabled() {
+			taints, err = interactive.GetString(interactive.Input{
+				Question: "Taints",
+				Help:     cmd.Flags().Lookup("taints").Usage,
+				Default:  taints,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+			}
+		}
+		return ocm.ParseTaints(taints)
+	}
+
+	addTaints := args.addTaints
+	removeTaints := args.removeTaints
+	if interactive.Enabled() {
+		addTaints, err = interactive.GetString(interactive.Input{
+			Question: "Add taints",
+			Help:     cmd.Flags().Lookup("add-taints").Usage,
+			Default:  addTaints,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+		}
+		removeTaints, err = interactive.GetString(interactive.Input{
+			Question: "Remove taints",
+			Help:     cmd.Flags().Lookup("remove-taints").Usage,
+			Default:  removeTaints,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+		}
+	}
+	add, err := ocm.ParseTaints(addTaints)
+	if err != nil {
+		return nil, err
+	}
+	return ocm.MergeTaints(existing, add, ocm.ParseKeys(removeTaints))
+}
+
+func printDiff(title string, before []string, after []string) {
+	fmt.Printf("%s:\n", title)
+	for _, line := range ocm.DiffItems(before, after) {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
+func equalItems(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,344 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	clusterKey           string
+	version              string
+	scheduleDate         string
+	scheduleTime         string
+	nodeDrainGracePeriod string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "upgrade",
+	Aliases: []string{"upgrades"},
+	Short:   "Edit a scheduled cluster upgrade",
+	Long:    "Edit the version, schedule or node drain grace period of a scheduled cluster upgrade",
+	Example: `  # Move the upgrade scheduled on a cluster named "mycluster" to a new date
+  rosa edit upgrade -c mycluster --schedule-date 2021-08-01 --schedule-time 02:00
+
+  # Interactively edit the upgrade scheduled on a cluster named "mycluster"
+  rosa edit upgrade -c mycluster --interactive`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to edit the scheduled upgrade of (required)",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift that the cluster will be upgraded to",
+	)
+
+	flags.StringVar(
+		&args.scheduleDate,
+		"schedule-date",
+		"",
+		"Next date the upgrade should run at the specified UTC time. Format should be 'yyyy-mm-dd'",
+	)
+
+	flags.StringVar(
+		&args.scheduleTime,
+		"schedule-time",
+		"",
+		"Next UTC time that the upgrade should run on the specified date. Format should be 'HH:mm'",
+	)
+
+	flags.StringVar(
+		&args.nodeDrainGracePeriod,
+		"node-drain-grace-period",
+		"",
+		fmt.Sprintf("Grace period for how long Pod Disruption Budget-protected workloads will be "+
+			"respected during upgrades. Valid options are ['%s']", strings.Join(ocm.NodeDrainOptions, "','")),
+	)
+
+	confirm.AddFlag(flags)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	scheduledUpgrade, upgradeState, err := ocmClient.GetScheduledUpgrade(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	if scheduledUpgrade == nil {
+		reporter.Errorf("There are no scheduled upgrades on cluster '%s'. To schedule one run:\n"+
+			"   rosa upgrade cluster -c %s", clusterKey, clusterKey)
+		os.Exit(1)
+	}
+	if upgradeState.Value() != cmv1.UpgradePolicyStateValuePending &&
+		upgradeState.Value() != cmv1.UpgradePolicyStateValueScheduled {
+		reporter.Errorf("The upgrade of cluster '%s' to version %s is %s and can no longer be edited",
+			clusterKey, scheduledUpgrade.Version(), upgradeState.Value())
+		os.Exit(1)
+	}
+
+	// Use the current values of the scheduled upgrade as defaults
+	currentNextRun := scheduledUpgrade.NextRun().UTC().Truncate(time.Minute)
+	currentNodeDrainGracePeriod := ocm.GetNodeDrainGracePeriod(cluster)
+
+	version := args.version
+	if version == "" {
+		version = scheduledUpgrade.Version()
+	}
+	scheduleDate := args.scheduleDate
+	if scheduleDate == "" {
+		scheduleDate = currentNextRun.Format("2006-01-02")
+	}
+	scheduleTime := args.scheduleTime
+	if scheduleTime == "" {
+		scheduleTime = currentNextRun.Format("15:04")
+	}
+	nodeDrainGracePeriod := args.nodeDrainGracePeriod
+	if nodeDrainGracePeriod == "" {
+		nodeDrainGracePeriod = currentNodeDrainGracePeriod
+	}
+
+	availableUpgrades, err := ocmClient.GetAvailableUpgrades(ocm.GetVersionID(cluster))
+	if err != nil {
+		reporter.Errorf("Failed to find available upgrades: %v", err)
+		os.Exit(1)
+	}
+
+	if interactive.Enabled() {
+		version, err = interactive.GetOption(interactive.Input{
+			Question: "Version",
+			Help:     cmd.Flags().Lookup("version").Usage,
+			Options:  availableUpgrades,
+			Default:  version,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid version to upgrade to: %s", err)
+			os.Exit(1)
+		}
+
+		scheduleDate, err = interactive.GetString(interactive.Input{
+			Question: "Please input desired date in format yyyy-mm-dd",
+			Help:     cmd.Flags().Lookup("schedule-date").Usage,
+			Default:  scheduleDate,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid date: %s", err)
+			os.Exit(1)
+		}
+
+		scheduleTime, err = interactive.GetString(interactive.Input{
+			Question: "Please input desired UTC time in format HH:mm",
+			Help:     cmd.Flags().Lookup("schedule-time").Usage,
+			Default:  scheduleTime,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid time: %s", err)
+			os.Exit(1)
+		}
+
+		nodeDrainGracePeriod, err = interactive.GetOption(interactive.Input{
+			Question: "Node draining",
+			Help:     cmd.Flags().Lookup("node-drain-grace-period").Usage,
+			Options:  ocm.NodeDrainOptions,
+			Default:  nodeDrainGracePeriod,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid node drain grace period: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	// Check that the version is still an available upgrade
+	validVersion := false
+	for _, v := range availableUpgrades {
+		if v == version {
+			validVersion = true
+			break
+		}
+	}
+	if !validVersion {
+		reporter.Errorf("Version %s is not an available upgrade for cluster '%s'. Available upgrades are [%s]",
+			version, clusterKey, strings.Join(availableUpgrades, ", "))
+		os.Exit(1)
+	}
+
+	// Parse next run to time.Time and check that it isn't in the past
+	nextRun, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("%s %s", scheduleDate, scheduleTime))
+	if err != nil {
+		reporter.Errorf("Schedule date should use the format 'yyyy-mm-dd'\n" +
+			"   Schedule time should use the format 'HH:mm'")
+		os.Exit(1)
+	}
+	if !nextRun.After(time.Now().UTC()) {
+		reporter.Errorf("Schedule date and time %s is in the past", nextRun.Format("2006-01-02 15:04 MST"))
+		os.Exit(1)
+	}
+
+	var nodeDrainValue float64
+	if nodeDrainGracePeriod != currentNodeDrainGracePeriod {
+		nodeDrainValue, err = ocm.ParseNodeDrainGracePeriod(nodeDrainGracePeriod)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+	}
+
+	// Build the patch with only the values that changed
+	changes := []string{}
+	upgradePolicyBuilder := cmv1.NewUpgradePolicy().ID(scheduledUpgrade.ID())
+	policyChanged := false
+	if version != scheduledUpgrade.Version() {
+		upgradePolicyBuilder = upgradePolicyBuilder.Version(version)
+		changes = append(changes, fmt.Sprintf("version from %s to %s", scheduledUpgrade.Version(), version))
+		policyChanged = true
+	}
+	if !nextRun.Equal(currentNextRun) {
+		upgradePolicyBuilder = upgradePolicyBuilder.NextRun(nextRun)
+		changes = append(changes, fmt.Sprintf("schedule from %s to %s",
+			currentNextRun.Format("2006-01-02 15:04 MST"), nextRun.Format("2006-01-02 15:04 MST")))
+		policyChanged = true
+	}
+	if nodeDrainValue != 0 {
+		changes = append(changes, fmt.Sprintf("node drain grace period from %s to %s",
+			currentNodeDrainGracePeriod, nodeDrainGracePeriod))
+	}
+	if len(changes) == 0 {
+		reporter.Infof("No changes to the scheduled upgrade of cluster '%s'", clusterKey)
+		os.Exit(0)
+	}
+
+	reporter.Infof("The scheduled upgrade of cluster '%s' will change:\n - %s",
+		clusterKey, strings.Join(changes, "\n - "))
+	if !confirm.Confirm("edit the scheduled upgrade of cluster %s", clusterKey) {
+		os.Exit(0)
+	}
+
+	if policyChanged {
+		upgradePolicy, err := upgradePolicyBuilder.Build()
+		if err != nil {
+			reporter.Errorf("Failed to edit scheduled upgrade for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+
+		reporter.Debugf("Updating upgrade policy '%s' on cluster '%s'", upgradePolicy.ID(), clusterKey)
+		err = ocmClient.UpdateUpgrade(cluster.ID(), upgradePolicy)
+		if err != nil {
+			reporter.Errorf("Failed to edit scheduled upgrade for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+	}
+
+	if nodeDrainValue != 0 {
+		err = ocmClient.UpdateCluster(cluster.ID(), awsCreator, ocm.Spec{
+			NodeDrainGracePeriodInMinutes: nodeDrainValue,
+		})
+		if err != nil {
+			reporter.Errorf("Failed to update cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+	}
+
+	reporter.Infof("Updated scheduled upgrade on cluster '%s'", clusterKey)
+}
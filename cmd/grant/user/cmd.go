@@ -17,10 +17,15 @@ limitations under the License.
 package user
 
 import (
+	"bufio"
+	"encoding/csv"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/arguments"
@@ -33,6 +38,7 @@ import (
 var args struct {
 	clusterKey string
 	username   string
+	fromFile   string
 }
 
 var Cmd = &cobra.Command{
@@ -44,9 +50,16 @@ var Cmd = &cobra.Command{
   rosa grant user cluster-admin --user=myusername --cluster=mycluster
 
   # Grant dedicated-admins role to a user
-  rosa grant user dedicated-admin --user=myusername --cluster=mycluster`,
+  rosa grant user dedicated-admin --user=myusername --cluster=mycluster
+
+  # Grant the roles listed in a CSV file with 'cluster,user,role' lines
+  rosa grant user --from-file=users.csv`,
 	Run: run,
 	Args: func(_ *cobra.Command, argv []string) error {
+		// The role is optional with a file, where it is the default for lines without one
+		if args.fromFile != "" && len(argv) <= 1 {
+			return nil
+		}
 		if len(argv) != 1 {
 			return fmt.Errorf(
 				"Expected exactly one command line argument containing the name " +
@@ -70,24 +83,39 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID of the cluster to add the user to (required).",
+		"Name or ID of the cluster to add the user to (required unless --from-file is used).",
 	)
-	Cmd.MarkFlagRequired("cluster")
 
 	flags.StringVarP(
 		&args.username,
 		"user",
 		"u",
 		"",
-		"Username to grant the role to (required).",
+		"Username to grant the role to (required unless --from-file is used).",
+	)
+
+	flags.StringVar(
+		&args.fromFile,
+		"from-file",
+		"",
+		"Path of a CSV file with one 'cluster,user,role' line for each role to grant. "+
+			"The role can be omitted when it is given as argument.",
 	)
-	Cmd.MarkFlagRequired("user")
 }
 
 func run(_ *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	if args.fromFile != "" {
+		runFromFile(reporter, logger, argv)
+		return
+	}
+	if args.clusterKey == "" || args.username == "" {
+		reporter.Errorf("Expected the '--cluster' and '--user' flags, or the '--from-file' flag")
+		os.Exit(1)
+	}
+
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
@@ -113,22 +141,9 @@ func run(_ *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	role := argv[0]
-	// Allow role aliases
-	for _, validAlias := range validRolesAliases {
-		if role == validAlias {
-			role = fmt.Sprintf("%ss", role)
-		}
-	}
-	isRoleValid := false
-	// Determine if role is valid
-	for _, validRole := range validRoles {
-		if role == validRole {
-			isRoleValid = true
-		}
-	}
-	if !isRoleValid {
-		reporter.Errorf("Expected at least one of %s", validRoles)
+	role, err := parseRole(argv[0])
+	if err != nil {
+		reporter.Errorf("%v", err)
 		os.Exit(1)
 	}
 
@@ -199,3 +214,197 @@ func run(_ *cobra.Command, argv []string) {
 
 	reporter.Infof("Granted role '%s' to user '%s' on cluster '%s'", role, username, clusterKey)
 }
+
+// parseRole returns the group of the given role, allowing the role aliases.
+func parseRole(role string) (string, error) {
+	for _, validAlias := range validRolesAliases {
+		if role == validAlias {
+			role = fmt.Sprintf("%ss", role)
+		}
+	}
+	for _, validRole := range validRoles {
+		if role == validRole {
+			return role, nil
+		}
+	}
+	return "", fmt.Errorf("Expected at least one of %s", validRoles)
+}
+
+type grant struct {
+	clusterKey string
+	username   string
+	role       string
+	result     string
+}
+
+// readGrants reads the 'cluster,user,role' lines of a CSV file, skipping comments and an optional
+// header line.
+func readGrants(path string, defaultRole string) ([]*grant, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open file '%s': %v", path, err)
+	}
+	defer file.Close()
+
+	grants := []*grant{}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		reader := csv.NewReader(strings.NewReader(text))
+		reader.TrimLeadingSpace = true
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read line %d of '%s': %v", line, path, err)
+		}
+		if len(grants) == 0 && strings.EqualFold(record[0], "cluster") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("Expected 'cluster,user,role' in line %d of '%s'", line, path)
+		}
+		g := &grant{
+			clusterKey: strings.TrimSpace(record[0]),
+			username:   strings.TrimSpace(record[1]),
+			role:       defaultRole,
+		}
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			g.role = strings.TrimSpace(record[2])
+		}
+		if !ocm.IsValidClusterKey(g.clusterKey) {
+			return nil, fmt.Errorf("Cluster '%s' in line %d isn't valid", g.clusterKey, line)
+		}
+		if !ocm.IsValidUsername(g.username) || g.username == "cluster-admin" {
+			return nil, fmt.Errorf("Username '%s' in line %d isn't valid", g.username, line)
+		}
+		if g.role == "" {
+			return nil, fmt.Errorf("Expected a role in line %d", line)
+		}
+		g.role, err = parseRole(g.role)
+		if err != nil {
+			return nil, fmt.Errorf("Role in line %d isn't valid: %v", line, err)
+		}
+		grants = append(grants, g)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read file '%s': %v", path, err)
+	}
+	return grants, nil
+}
+
+func runFromFile(reporter *rprtr.Object, logger *logrus.Logger, argv []string) {
+	defaultRole := ""
+	if len(argv) == 1 {
+		defaultRole = argv[0]
+	}
+
+	// Check the whole file before granting anything:
+	grants, err := readGrants(args.fromFile, defaultRole)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	if len(grants) == 0 {
+		reporter.Warnf("There are no users in file '%s'", args.fromFile)
+		os.Exit(0)
+	}
+
+	// Get AWS region
+	region, err := aws.GetRegion(arguments.GetRegion())
+	if err != nil {
+		reporter.Errorf("Error getting region: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Region(region).
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	clusters := map[string]*cmv1.Cluster{}
+	failed := false
+	for _, g := range grants {
+		cluster, ok := clusters[g.clusterKey]
+		if !ok {
+			reporter.Debugf("Loading cluster '%s'", g.clusterKey)
+			cluster, err = ocmClient.GetCluster(g.clusterKey, awsCreator)
+			if err != nil {
+				g.result = fmt.Sprintf("failed: %v", err)
+				failed = true
+				continue
+			}
+			clusters[g.clusterKey] = cluster
+		}
+		if cluster.State() != cmv1.ClusterStateReady {
+			g.result = "failed: cluster is not yet ready"
+			failed = true
+			continue
+		}
+
+		existing, err := ocmClient.GetUser(cluster.ID(), g.role, g.username)
+		if err != nil {
+			g.result = fmt.Sprintf("failed: %v", err)
+			failed = true
+			continue
+		}
+		if existing != nil {
+			g.result = "already granted"
+			continue
+		}
+
+		reporter.Debugf("Adding user '%s' to group '%s' in cluster '%s'", g.username, g.role, g.clusterKey)
+		user, err := cmv1.NewUser().ID(g.username).Build()
+		if err == nil {
+			_, err = ocmClient.CreateUser(cluster.ID(), g.role, user)
+		}
+		if err != nil {
+			g.result = fmt.Sprintf("failed: %v", err)
+			failed = true
+			continue
+		}
+		g.result = "granted"
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CLUSTER\tUSER\tROLE\tRESULT\n")
+	for _, g := range grants {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", g.clusterKey, g.username, g.role, g.result)
+	}
+	writer.Flush()
+
+	if failed {
+		reporter.Errorf("Failed to grant some of the roles in '%s'", args.fromFile)
+		os.Exit(1)
+	}
+}
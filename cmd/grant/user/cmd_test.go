@@ -0,0 +1,112 @@
+package user_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/cmd/grant/user"
+)
+
+var _ = Describe("Grant user from file", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "grants")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	readGrants := func(content string, defaultRole string) ([]string, error) {
+		path := filepath.Join(dir, "users.csv")
+		Expect(ioutil.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		grants, err := user.ReadGrants(path, defaultRole)
+		if err != nil {
+			return nil, err
+		}
+		summaries := []string{}
+		for _, g := range grants {
+			summaries = append(summaries, g.Summary())
+		}
+		return summaries, nil
+	}
+
+	DescribeTable("reads the grants",
+		func(content string, defaultRole string, expected []string) {
+			grants, err := readGrants(content, defaultRole)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(grants).To(Equal(expected))
+		},
+		Entry("empty file", "", "", []string{}),
+		Entry("roles",
+			"prod,alice,dedicated-admins\ndev,bob,cluster-admins\n", "",
+			[]string{"prod,alice,dedicated-admins", "dev,bob,cluster-admins"}),
+		Entry("header",
+			"cluster,user,role\nprod,alice,dedicated-admins\n", "",
+			[]string{"prod,alice,dedicated-admins"}),
+		Entry("header in upper case",
+			"CLUSTER,USER,ROLE\nprod,alice,dedicated-admins\n", "",
+			[]string{"prod,alice,dedicated-admins"}),
+		Entry("header after comments",
+			"# Users of the team\n\ncluster,user,role\nprod,alice,dedicated-admins\n", "",
+			[]string{"prod,alice,dedicated-admins"}),
+		Entry("comments and blank lines",
+			"# Production\nprod,alice,dedicated-admins\n\n  # Development\ndev,bob,dedicated-admins\n", "",
+			[]string{"prod,alice,dedicated-admins", "dev,bob,dedicated-admins"}),
+		Entry("role aliases",
+			"prod,alice,dedicated-admin\ndev,bob,cluster-admin\n", "",
+			[]string{"prod,alice,dedicated-admins", "dev,bob,cluster-admins"}),
+		Entry("default role without a role column",
+			"prod,alice\n", "dedicated-admin",
+			[]string{"prod,alice,dedicated-admins"}),
+		Entry("default role with an empty role column",
+			"prod,alice,\n", "cluster-admins",
+			[]string{"prod,alice,cluster-admins"}),
+		Entry("role that overrides the default",
+			"prod,alice,cluster-admin\n", "dedicated-admin",
+			[]string{"prod,alice,cluster-admins"}),
+		Entry("spaces around fields",
+			"  prod , alice , dedicated-admins \n", "",
+			[]string{"prod,alice,dedicated-admins"}),
+	)
+
+	DescribeTable("rejects invalid lines",
+		func(content string, defaultRole string, message string) {
+			_, err := readGrants(content, defaultRole)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("too few fields",
+			"prod\n", "dedicated-admin", "Expected 'cluster,user,role' in line 1"),
+		Entry("too many fields",
+			"prod,alice,dedicated-admins,extra\n", "", "Expected 'cluster,user,role' in line 1"),
+		Entry("second header",
+			"prod,alice,dedicated-admins\ncluster,user,role\n", "", "Role in line 2 isn't valid"),
+		Entry("invalid role",
+			"# Users\nprod,alice,admins\n", "", "Role in line 2 isn't valid"),
+		Entry("invalid default role",
+			"prod,alice\n", "admins", "Role in line 1 isn't valid"),
+		Entry("missing role",
+			"prod,alice\n", "", "Expected a role in line 1"),
+		Entry("reserved username",
+			"prod,cluster-admin,dedicated-admins\n", "", "Username 'cluster-admin' in line 1 isn't valid"),
+		Entry("invalid username",
+			"prod,alice:smith,dedicated-admins\n", "", "Username 'alice:smith' in line 1 isn't valid"),
+		Entry("invalid cluster",
+			"prod;drop,alice,dedicated-admins\n", "", "Cluster 'prod;drop' in line 1 isn't valid"),
+		Entry("malformed quotes",
+			"prod,\"alice,dedicated-admins\n", "", "Failed to read line 1"),
+	)
+
+	It("fails if the file doesn't exist", func() {
+		_, err := user.ReadGrants(filepath.Join(dir, "missing.csv"), "")
+		Expect(err).To(MatchError(ContainSubstring("Failed to open file")))
+	})
+})
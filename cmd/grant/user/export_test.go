@@ -0,0 +1,11 @@
+package user
+
+// Unexported types and functions used by the tests of the package
+type Grant = grant
+
+var ReadGrants = readGrants
+
+// Summary returns the cluster, user and role of the grant, so that tests can compare it.
+func (g *grant) Summary() string {
+	return g.clusterKey + "," + g.username + "," + g.role
+}
@@ -0,0 +1,359 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	clusterKey string
+	file       string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Import the day-2 configuration of a cluster",
+	Long: "Recreate the machine pools, identity providers, ingresses, add-ons, users and upgrade " +
+		"policy exported with 'rosa export cluster' on a cluster. Resources that already exist on " +
+		"the cluster are left unchanged.\n\n" +
+		"The secrets of identity providers are read from the environment variables referenced " +
+		"in the file.",
+	Example: `  # Import the configuration in "cluster.yaml" into a cluster named "mycluster"
+  GITHUB_1_CLIENT_SECRET=... rosa import cluster --cluster=mycluster -f cluster.yaml`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to import the configuration into (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"Path of the file created by 'rosa export cluster' (required).",
+	)
+	Cmd.MarkFlagRequired("file")
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	config, err := ocm.LoadClusterConfig(args.file)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	// Check the whole file before making any change, so that a missing secret doesn't leave
+	// the cluster half configured:
+	machinePools := []*cmv1.MachinePool{}
+	for _, machinePoolConfig := range config.MachinePools {
+		machinePool, err := machinePoolConfig.Build()
+		if err != nil {
+			reporter.Errorf("Failed to read machine pool '%s': %v", machinePoolConfig.ID, err)
+			os.Exit(1)
+		}
+		machinePools = append(machinePools, machinePool)
+	}
+	idps := []*cmv1.IdentityProvider{}
+	for _, idpConfig := range config.IdentityProviders {
+		idp, err := ocm.BuildIdentityProvider(idpConfig)
+		if err != nil {
+			reporter.Errorf("Failed to read identity provider: %v", err)
+			os.Exit(1)
+		}
+		idps = append(idps, idp)
+	}
+	var nodeDrainGracePeriod float64
+	if config.Upgrade != nil && config.Upgrade.NodeDrainGracePeriod != "" {
+		nodeDrainGracePeriod, err = ocm.ParseNodeDrainGracePeriod(config.Upgrade.NodeDrainGracePeriod)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	if !confirm.Confirm("import the configuration of '%s' into cluster '%s'", config.Cluster, clusterKey) {
+		os.Exit(0)
+	}
+
+	failed := false
+	fail := func(format string, args ...interface{}) {
+		reporter.Errorf(format, args...)
+		failed = true
+	}
+
+	// Machine pools:
+	existingMachinePools, err := ocmClient.GetMachinePools(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	for _, machinePool := range machinePools {
+		exists := false
+		for _, existing := range existingMachinePools {
+			exists = exists || existing.ID() == machinePool.ID()
+		}
+		if exists {
+			reporter.Warnf("Machine pool '%s' already exists, skipping", machinePool.ID())
+			continue
+		}
+		_, err = ocmClient.CreateMachinePool(cluster.ID(), machinePool)
+		if err != nil {
+			fail("Failed to create machine pool '%s': %v", machinePool.ID(), err)
+			continue
+		}
+		reporter.Infof("Created machine pool '%s'", machinePool.ID())
+	}
+
+	// Identity providers:
+	existingIdps, err := ocmClient.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	for _, idp := range idps {
+		exists := false
+		for _, existing := range existingIdps {
+			exists = exists || existing.Name() == idp.Name()
+		}
+		if exists {
+			reporter.Warnf("Identity provider '%s' already exists, skipping", idp.Name())
+			continue
+		}
+		_, err = ocmClient.CreateIdentityProvider(cluster.ID(), idp)
+		if err != nil {
+			fail("Failed to create identity provider '%s': %v", idp.Name(), err)
+			continue
+		}
+		reporter.Infof("Created identity provider '%s'", idp.Name())
+	}
+
+	// Ingresses, the default one is updated in place:
+	existingIngresses, err := ocmClient.GetIngresses(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get ingresses for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	for _, ingressConfig := range config.Ingresses {
+		if ingressConfig.Default {
+			for _, existing := range existingIngresses {
+				if !existing.Default() {
+					continue
+				}
+				ingress, err := ingressConfig.Build(existing.ID())
+				if err == nil {
+					_, err = ocmClient.UpdateIngress(cluster.ID(), ingress)
+				}
+				if err != nil {
+					fail("Failed to update default ingress: %v", err)
+					continue
+				}
+				reporter.Infof("Updated default ingress")
+			}
+			continue
+		}
+		exists := false
+		for _, existing := range existingIngresses {
+			exists = exists || !existing.Default()
+		}
+		if exists {
+			reporter.Warnf("Cluster '%s' already has an additional ingress, skipping", clusterKey)
+			continue
+		}
+		ingress, err := ingressConfig.Build("")
+		if err == nil {
+			_, err = ocmClient.CreateIngress(cluster.ID(), ingress)
+		}
+		if err != nil {
+			fail("Failed to create additional ingress: %v", err)
+			continue
+		}
+		reporter.Infof("Created additional ingress")
+	}
+
+	// Add-ons:
+	existingAddOns, err := ocmClient.GetAddOnInstallations(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get add-ons for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	for _, addOnConfig := range config.AddOns {
+		exists := false
+		for _, existing := range existingAddOns {
+			exists = exists || existing.Addon().ID() == addOnConfig.ID
+		}
+		if exists {
+			reporter.Warnf("Add-on '%s' is already installed, skipping", addOnConfig.ID)
+			continue
+		}
+		err = ocmClient.InstallAddOn(cluster.ID(), awsCreator, addOnConfig.ID, addOnConfig.Params())
+		if err != nil {
+			fail("Failed to install add-on '%s': %v", addOnConfig.ID, err)
+			continue
+		}
+		reporter.Infof("Installing add-on '%s'", addOnConfig.ID)
+	}
+
+	// Users:
+	for _, group := range ocm.UserGroups {
+		if len(config.Users[group]) == 0 {
+			continue
+		}
+		existingUsers, err := ocmClient.GetUsers(cluster.ID(), group)
+		if err != nil {
+			reporter.Errorf("Failed to get users of group '%s' for cluster '%s': %v", group, clusterKey, err)
+			os.Exit(1)
+		}
+		for _, username := range config.Users[group] {
+			exists := false
+			for _, existing := range existingUsers {
+				exists = exists || existing.ID() == username
+			}
+			if exists {
+				reporter.Warnf("User '%s' is already in group '%s', skipping", username, group)
+				continue
+			}
+			user, err := cmv1.NewUser().ID(username).Build()
+			if err == nil {
+				_, err = ocmClient.CreateUser(cluster.ID(), group, user)
+			}
+			if err != nil {
+				fail("Failed to grant '%s' to user '%s': %v", group, username, err)
+				continue
+			}
+			reporter.Infof("Granted '%s' to user '%s'", group, username)
+		}
+	}
+
+	// Upgrade policy:
+	if config.Upgrade != nil && config.Upgrade.Schedule != "" {
+		existingPolicies, err := ocmClient.GetUpgradePolicies(cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get upgrade policies for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		if len(existingPolicies) > 0 {
+			reporter.Warnf("Cluster '%s' already has an upgrade scheduled, skipping", clusterKey)
+		} else {
+			upgradePolicy, err := cmv1.NewUpgradePolicy().
+				ScheduleType("automatic").
+				Schedule(config.Upgrade.Schedule).
+				Build()
+			if err == nil {
+				err = ocmClient.ScheduleUpgrade(cluster.ID(), upgradePolicy)
+			}
+			if err != nil {
+				fail("Failed to schedule automatic upgrades: %v", err)
+			} else {
+				reporter.Infof("Scheduled automatic upgrades with schedule '%s'", config.Upgrade.Schedule)
+			}
+		}
+	}
+	if nodeDrainGracePeriod != 0 {
+		err = ocmClient.UpdateCluster(cluster.ID(), awsCreator, ocm.Spec{
+			NodeDrainGracePeriodInMinutes: nodeDrainGracePeriod,
+		})
+		if err != nil {
+			fail("Failed to set node drain grace period: %v", err)
+		} else {
+			reporter.Infof("Set node drain grace period to %s", config.Upgrade.NodeDrainGracePeriod)
+		}
+	}
+
+	if failed {
+		reporter.Errorf("Configuration of '%s' was partially imported into cluster '%s'", config.Cluster, clusterKey)
+		os.Exit(1)
+	}
+	reporter.Infof("Imported configuration of '%s' into cluster '%s'", config.Cluster, clusterKey)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
@@ -47,6 +48,8 @@ var Cmd = &cobra.Command{
 func init() {
 	flags := Cmd.Flags()
 
+	output.AddFlag(Cmd)
+
 	flags.StringVarP(
 		&args.clusterKey,
 		"cluster",
@@ -94,6 +97,14 @@ func run(_ *cobra.Command, _ []string) {
 			reporter.Errorf("Failed to fetch add-ons: %v", err)
 			os.Exit(1)
 		}
+		if output.HasFlag() {
+			err = output.Print(addOnResources)
+			if err != nil {
+				reporter.Errorf("%s", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 		if len(addOnResources) == 0 {
 			reporter.Infof("There are no add-ons available")
 			os.Exit(0)
@@ -150,6 +161,15 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if output.HasFlag() {
+		err = output.Print(clusterAddOns)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if len(clusterAddOns) == 0 {
 		reporter.Infof("There are no add-ons installed on cluster '%s'", clusterKey)
 		os.Exit(0)
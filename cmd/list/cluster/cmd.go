@@ -19,8 +19,11 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/arguments"
@@ -28,16 +31,130 @@ import (
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/properties"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
+var args struct {
+	filter  string
+	columns string
+	sortBy  string
+}
+
+// column describes how to print a cluster attribute. Columns with a sort function are sorted
+// using it, the rest are sorted alphabetically by their printed value.
+type column struct {
+	header string
+	value  func(*cmv1.Cluster) string
+	less   func(a, b *cmv1.Cluster) bool
+}
+
+var columns = map[string]column{
+	"id": {
+		header: "ID",
+		value:  (*cmv1.Cluster).ID,
+	},
+	"name": {
+		header: "NAME",
+		value:  (*cmv1.Cluster).Name,
+	},
+	"state": {
+		header: "STATE",
+		value: func(cluster *cmv1.Cluster) string {
+			return string(cluster.State())
+		},
+	},
+	"region": {
+		header: "REGION",
+		value: func(cluster *cmv1.Cluster) string {
+			return cluster.Region().ID()
+		},
+	},
+	"version": {
+		header: "VERSION",
+		value:  (*cmv1.Cluster).OpenshiftVersion,
+	},
+	"multi-az": {
+		header: "MULTI-AZ",
+		value: func(cluster *cmv1.Cluster) string {
+			return yesNo(cluster.MultiAZ())
+		},
+	},
+	"sts": {
+		header: "STS",
+		value: func(cluster *cmv1.Cluster) string {
+			return yesNo(cluster.AWS().STS().RoleARN() != "")
+		},
+	},
+	"private": {
+		header: "PRIVATE",
+		value: func(cluster *cmv1.Cluster) string {
+			return yesNo(cluster.API().Listening() == cmv1.ListeningMethodInternal)
+		},
+	},
+	"creator": {
+		header: "CREATOR",
+		value: func(cluster *cmv1.Cluster) string {
+			return cluster.Properties()[properties.CreatorARN]
+		},
+	},
+	"created": {
+		header: "CREATED",
+		value: func(cluster *cmv1.Cluster) string {
+			return cluster.CreationTimestamp().Format("2006-01-02 15:04 MST")
+		},
+		less: func(a, b *cmv1.Cluster) bool {
+			return a.CreationTimestamp().Before(b.CreationTimestamp())
+		},
+	},
+	"expiration": {
+		header: "EXPIRATION",
+		value: func(cluster *cmv1.Cluster) string {
+			if cluster.ExpirationTimestamp().IsZero() {
+				return ""
+			}
+			return cluster.ExpirationTimestamp().Format("2006-01-02 15:04 MST")
+		},
+		less: func(a, b *cmv1.Cluster) bool {
+			return a.ExpirationTimestamp().Before(b.ExpirationTimestamp())
+		},
+	},
+	"nodes": {
+		header: "NODES",
+		value: func(cluster *cmv1.Cluster) string {
+			minNodes, maxNodes := getComputeNodes(cluster)
+			if minNodes != maxNodes {
+				return fmt.Sprintf("%d-%d", minNodes, maxNodes)
+			}
+			return fmt.Sprintf("%d", minNodes)
+		},
+		less: func(a, b *cmv1.Cluster) bool {
+			_, maxA := getComputeNodes(a)
+			_, maxB := getComputeNodes(b)
+			return maxA < maxB
+		},
+	},
+}
+
+// Order in which the available columns are shown in the help and completion
+var columnNames = []string{
+	"id", "name", "state", "region", "version", "multi-az", "sts", "private",
+	"creator", "created", "expiration", "nodes",
+}
+
 var Cmd = &cobra.Command{
 	Use:     "clusters",
 	Aliases: []string{"cluster"},
 	Short:   "List clusters",
 	Long:    "List clusters.",
 	Example: `  # List all clusters
-  rosa list clusters`,
+  rosa list clusters
+
+  # List all ready clusters in the us-east-1 region, oldest first
+  rosa list clusters --filter "state = 'ready' AND region.id = 'us-east-1'" --sort-by created
+
+  # List clusters with their version and number of compute nodes
+  rosa list clusters --columns id,name,version,nodes`,
 	Args: cobra.NoArgs,
 	Run:  run,
 }
@@ -48,12 +165,60 @@ func init() {
 
 	arguments.AddRegionFlag(flags)
 	output.AddFlag(Cmd)
+
+	flags.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"Search query used to filter the clusters, using the OCM search syntax, "+
+			"for example \"state = 'ready'\".",
+	)
+
+	flags.StringVar(
+		&args.columns,
+		"columns",
+		"id,name,state",
+		fmt.Sprintf("Comma-separated list of columns to show. Allowed columns are %s", columnNames),
+	)
+	Cmd.RegisterFlagCompletionFunc("columns", columnCompletion)
+
+	flags.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		fmt.Sprintf("Column to sort the clusters by. Allowed columns are %s", columnNames),
+	)
+	Cmd.RegisterFlagCompletionFunc("sort-by", columnCompletion)
+}
+
+func columnCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return columnNames, cobra.ShellCompDirectiveDefault
 }
 
 func run(_ *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	var selected []column
+	for _, name := range strings.Split(args.columns, ",") {
+		col, ok := columns[strings.TrimSpace(name)]
+		if !ok {
+			reporter.Errorf("Unknown column '%s'. Allowed columns are %s", name, columnNames)
+			os.Exit(1)
+		}
+		selected = append(selected, col)
+	}
+
+	var sortColumn *column
+	if args.sortBy != "" {
+		col, ok := columns[args.sortBy]
+		if !ok {
+			reporter.Errorf("Unknown column '%s'. Allowed columns are %s", args.sortBy, columnNames)
+			os.Exit(1)
+		}
+		sortColumn = &col
+	}
+
 	// Create the AWS client:
 	awsClient, err := aws.NewClient().
 		Region(arguments.GetRegion()).
@@ -86,12 +251,21 @@ func run(_ *cobra.Command, _ []string) {
 	}()
 
 	// Retrieve the list of clusters:
-	clusters, err := ocmClient.GetClusters(awsCreator, 1000)
+	clusters, err := ocmClient.FindClusters(awsCreator, args.filter)
 	if err != nil {
 		reporter.Errorf("Failed to get clusters: %v", err)
 		os.Exit(1)
 	}
 
+	if sortColumn != nil {
+		sort.SliceStable(clusters, func(i, j int) bool {
+			if sortColumn.less != nil {
+				return sortColumn.less(clusters[i], clusters[j])
+			}
+			return sortColumn.value(clusters[i]) < sortColumn.value(clusters[j])
+		})
+	}
+
 	if output.HasFlag() {
 		err = output.Print(clusters)
 		if err != nil {
@@ -108,15 +282,33 @@ func run(_ *cobra.Command, _ []string) {
 
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tNAME\tSTATE\n")
+	headers := make([]string, len(selected))
+	for i, col := range selected {
+		headers[i] = col.header
+	}
+	fmt.Fprintf(writer, "%s\n", strings.Join(headers, "\t"))
 	for _, cluster := range clusters {
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\n",
-			cluster.ID(),
-			cluster.Name(),
-			cluster.State(),
-		)
+		values := make([]string, len(selected))
+		for i, col := range selected {
+			values[i] = col.value(cluster)
+		}
+		fmt.Fprintf(writer, "%s\n", strings.Join(values, "\t"))
 	}
 	writer.Flush()
 }
+
+// getComputeNodes returns the minimum and maximum number of compute nodes of the cluster, not
+// including additional machine pools.
+func getComputeNodes(cluster *cmv1.Cluster) (int, int) {
+	if cluster.Nodes().AutoscaleCompute() != nil {
+		return cluster.Nodes().AutoscaleCompute().MinReplicas(), cluster.Nodes().AutoscaleCompute().MaxReplicas()
+	}
+	return cluster.Nodes().Compute(), cluster.Nodes().Compute()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
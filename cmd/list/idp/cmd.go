@@ -19,7 +19,6 @@ package idp
 import (
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -150,12 +149,7 @@ func run(_ *cobra.Command, _ []string) {
 		if idpType == "htpasswd" {
 			continue
 		}
-		fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", idp.Name(), idpType, getAuthURL(cluster, idp.Name()))
+		fmt.Fprintf(writer, "%s\t\t%s\t\t%s\n", idp.Name(), idpType, ocm.GetOAuthCallbackURL(cluster, idp.Name()))
 	}
 	writer.Flush()
 }
-
-func getAuthURL(cluster *cmv1.Cluster, idpName string) string {
-	oauthURL := strings.Replace(cluster.Console().URL(), "console-openshift-console", "oauth-openshift", 1)
-	return fmt.Sprintf("%s/oauth2callback/%s", oauthURL, idpName)
-}
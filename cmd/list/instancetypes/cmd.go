@@ -30,17 +30,65 @@ import (
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
+var args struct {
+	minCPU    int
+	minMemory int
+	category  string
+	multiAZ   bool
+	sortBy    string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "instance-types",
 	Aliases: []string{"instancetypes"},
 	Short:   "List Instance types",
 	Long:    "List Instance types that are available for use with ROSA.",
 	Example: `  # List all instance types
-  rosa list instance-types`,
+  rosa list instance-types
+
+  # List memory optimized instance types with at least 8 cores and 64 GiB of memory
+  rosa list instance-types --category=memory --min-cpu=8 --min-memory=64 --sort-by=memory`,
 	Run: run,
 }
 
 func init() {
+	flags := Cmd.Flags()
+
+	flags.IntVar(
+		&args.minCPU,
+		"min-cpu",
+		0,
+		"Only list instance types with at least this number of CPU cores.",
+	)
+
+	flags.IntVar(
+		&args.minMemory,
+		"min-memory",
+		0,
+		"Only list instance types with at least this amount of memory, in GiB.",
+	)
+
+	flags.StringVar(
+		&args.category,
+		"category",
+		"",
+		"Only list instance types of this category: 'general', 'memory', 'compute' or 'accelerated'.",
+	)
+
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Only list instance types that there is quota for in a multi-AZ cluster.",
+	)
+
+	flags.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		"Sort the instance types by 'id', 'category', 'cpu' or 'memory'.",
+	)
+
 	output.AddFlag(Cmd)
 }
 
@@ -48,6 +96,24 @@ func run(cmd *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	if args.minCPU < 0 || args.minMemory < 0 {
+		reporter.Errorf("The minimum CPU cores and memory must be non-negative integers")
+		os.Exit(1)
+	}
+	filter := ocm.MachineTypeFilter{
+		MinCPU:    args.minCPU,
+		MinMemory: float64(args.minMemory) * (1 << 30),
+		MultiAZ:   args.multiAZ,
+	}
+	if args.category != "" {
+		category, err := ocm.ParseMachineTypeCategory(args.category)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		filter.Category = category
+	}
+
 	// Create the client for the OCM API:
 	ocmClient, err := ocm.NewClient().
 		Logger(logger).
@@ -76,6 +142,27 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	// Without filters all the instance types are kept, so that the output contains the ones that
+	// aren't available too:
+	filtered := false
+	for _, flag := range []string{"min-cpu", "min-memory", "category", "multi-az"} {
+		filtered = filtered || cmd.Flags().Changed(flag)
+	}
+	if filtered {
+		machineTypes = ocm.FilterMachineTypes(machineTypes, filter)
+		if len(machineTypes) == 0 {
+			reporter.Infof("There are no instance types that match the given filters")
+			os.Exit(0)
+		}
+	}
+	if args.sortBy != "" {
+		err = ocm.SortMachineTypes(machineTypes, args.sortBy)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+	}
+
 	if output.HasFlag() {
 		var instanceTypes []*cmv1.MachineType
 		for _, machine := range machineTypes {
@@ -101,26 +188,9 @@ func run(cmd *cobra.Command, _ []string) {
 		fmt.Fprintf(writer,
 			"%s\t%s\t%d\t%s\n",
 			availableMachine.ID(), availableMachine.Category(), int(availableMachine.CPU().Value()),
-			ByteCountIEC(int(availableMachine.Memory().Value()),
+			ocm.ByteCountIEC(int(availableMachine.Memory().Value()),
 				availableMachine.Memory().Unit()),
 		)
 	}
 	writer.Flush()
 }
-
-func ByteCountIEC(b int, uValue string) string {
-	var unit int
-	if uValue == "B" {
-		unit = 1024
-	}
-	if b < unit {
-		return fmt.Sprintf("%d B", b)
-	}
-	div, exp := int64(unit), 0
-	for n := b / unit; n >= unit; n /= unit {
-		div *= int64(unit)
-		exp++
-	}
-	return fmt.Sprintf("%.1f %ciB",
-		float64(b)/float64(div), "KMGTPE"[exp])
-}
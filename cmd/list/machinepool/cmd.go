@@ -129,21 +129,7 @@ func run(_ *cobra.Command, _ []string) {
 	}
 
 	// Add default machine pool to the list
-	defaultMachinePoolBuilder := cmv1.NewMachinePool().
-		ID("Default").
-		AvailabilityZones(cluster.Nodes().AvailabilityZones()...).
-		InstanceType(cluster.Nodes().ComputeMachineType().ID()).
-		Labels(cluster.Nodes().ComputeLabels()).
-		Replicas(cluster.Nodes().Compute())
-	if cluster.Nodes().AutoscaleCompute() != nil {
-		defaultMachinePoolBuilder = defaultMachinePoolBuilder.Autoscaling(
-			cmv1.NewMachinePoolAutoscaling().
-				MinReplicas(cluster.Nodes().AutoscaleCompute().MinReplicas()).
-				MaxReplicas(cluster.Nodes().AutoscaleCompute().MaxReplicas()),
-		)
-	}
-	defaultMachinePool, _ := defaultMachinePoolBuilder.Build()
-
+	defaultMachinePool := ocm.DefaultMachinePool(cluster)
 	machinePools = append([]*cmv1.MachinePool{defaultMachinePool}, machinePools...)
 
 	if output.HasFlag() {
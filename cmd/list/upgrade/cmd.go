@@ -29,11 +29,19 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
 var args struct {
 	clusterKey string
+	to         string
+	dot        bool
+}
+
+type upgradeVersion struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes,omitempty"`
 }
 
 var Cmd = &cobra.Command{
@@ -41,12 +49,22 @@ var Cmd = &cobra.Command{
 	Aliases: []string{"upgrade"},
 	Short:   "List available cluster upgrades",
 	Long:    "List available and scheduled cluster version upgrades",
-	Run:     run,
+	Example: `  # List available upgrades for a cluster named "mycluster"
+  rosa list upgrades -c mycluster
+
+  # Show the shortest upgrade path from the current version to version 4.8.2
+  rosa list upgrades -c mycluster --to 4.8.2
+
+  # Render the upgrade graph of the cluster in DOT format
+  rosa list upgrades -c mycluster --to 4.8.2 --dot | dot -Tpng > upgrades.png`,
+	Run: run,
 }
 
 func init() {
 	flags := Cmd.Flags()
 
+	output.AddFlag(Cmd)
+
 	flags.StringVarP(
 		&args.clusterKey,
 		"cluster",
@@ -55,6 +73,20 @@ func init() {
 		"Name or ID of the cluster to list the upgrades of (required).",
 	)
 	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.to,
+		"to",
+		"",
+		"Target version. Shows the shortest sequence of upgrades needed to reach it.",
+	)
+
+	flags.BoolVar(
+		&args.dot,
+		"dot",
+		false,
+		"Print the graph of versions that the cluster can be upgraded to in DOT format.",
+	)
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -116,6 +148,11 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if args.to != "" || args.dot {
+		listUpgradePath(reporter, ocmClient, cluster)
+		return
+	}
+
 	// Load available upgrades for this cluster
 	reporter.Debugf("Loading available upgrades for cluster '%s'", clusterKey)
 	availableUpgrades, err := ocmClient.GetAvailableUpgrades(ocm.GetVersionID(cluster))
@@ -138,9 +175,7 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "VERSION\tNOTES\n")
+	upgrades := make([]*upgradeVersion, len(availableUpgrades))
 	for i, availableUpgrade := range availableUpgrades {
 		notes := ""
 		if notes == "" && (i == 0 || availableUpgrade == latestRev) {
@@ -150,11 +185,113 @@ func run(_ *cobra.Command, _ []string) {
 			notes = fmt.Sprintf("%s for %s", upgradeState.Value(),
 				scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
 		}
-		fmt.Fprintf(writer, "%s\t%s\n", availableUpgrade, notes)
+		upgrades[i] = &upgradeVersion{Version: availableUpgrade, Notes: notes}
+	}
+
+	if output.HasFlag() {
+		err = output.Print(upgrades)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "VERSION\tNOTES\n")
+	for _, upgrade := range upgrades {
+		fmt.Fprintf(writer, "%s\t%s\n", upgrade.Version, upgrade.Notes)
+	}
+	writer.Flush()
+}
+
+func listUpgradePath(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster) {
+	clusterKey := args.clusterKey
+
+	var path []string
+	var err error
+	if args.to != "" {
+		reporter.Debugf("Loading upgrade path for cluster '%s' to version %s", clusterKey, args.to)
+		path, err = ocmClient.GetUpgradePath(cluster, args.to)
+		if err != nil {
+			reporter.Errorf("Failed to get upgrade path for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+	}
+
+	if args.dot {
+		reporter.Debugf("Loading versions in channel group '%s'", cluster.Version().ChannelGroup())
+		versions, err := ocmClient.GetUpgradeVersions(cluster)
+		if err != nil {
+			reporter.Errorf("Failed to get versions: %v", err)
+			os.Exit(1)
+		}
+		printDOT(ocm.GetUpgradeGraph(versions), cluster.OpenshiftVersion(), path)
+		return
+	}
+
+	steps := []*upgradeVersion{{Version: cluster.OpenshiftVersion(), Notes: "current"}}
+	for i, version := range path {
+		notes := ""
+		if i == len(path)-1 {
+			notes = "target"
+		}
+		steps = append(steps, &upgradeVersion{Version: version, Notes: notes})
+	}
+
+	if output.HasFlag() {
+		err = output.Print(steps)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "STEP\tVERSION\tNOTES\n")
+	for i, step := range steps {
+		fmt.Fprintf(writer, "%d\t%s\t%s\n", i, step.Version, step.Notes)
 	}
 	writer.Flush()
 }
 
+// printDOT prints the part of the upgrade graph that is reachable from the current version in
+// DOT format. Edges along the given path are highlighted.
+func printDOT(graph map[string][]string, current string, path []string) {
+	highlighted := map[string]string{}
+	from := current
+	for _, version := range path {
+		highlighted[from] = version
+		from = version
+	}
+
+	fmt.Printf("digraph upgrades {\n")
+	fmt.Printf("  \"%s\" [shape=box, style=bold];\n", current)
+	if len(path) > 0 {
+		fmt.Printf("  \"%s\" [shape=box, style=bold];\n", path[len(path)-1])
+	}
+	seen := map[string]bool{current: true}
+	queue := []string{current}
+	for len(queue) > 0 {
+		version := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[version] {
+			if highlighted[version] == next {
+				fmt.Printf("  \"%s\" -> \"%s\" [color=blue, penwidth=2];\n", version, next)
+			} else {
+				fmt.Printf("  \"%s\" -> \"%s\";\n", version, next)
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	fmt.Printf("}\n")
+}
+
 func latestInCurrentMinor(current string, versions []string) string {
 	latestVersion := current
 	currentParts := strings.Split(current, ".")
@@ -19,6 +19,7 @@ package user
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
@@ -36,6 +38,11 @@ var args struct {
 	clusterKey string
 }
 
+type userGroups struct {
+	ID     string   `json:"id"`
+	Groups []string `json:"groups"`
+}
+
 var Cmd = &cobra.Command{
 	Use:     "users",
 	Aliases: []string{"user"},
@@ -50,6 +57,7 @@ func init() {
 	flags := Cmd.Flags()
 
 	arguments.AddRegionFlag(flags)
+	output.AddFlag(Cmd)
 
 	flags.StringVarP(
 		&args.clusterKey,
@@ -160,12 +168,29 @@ func run(_ *cobra.Command, _ []string) {
 		}
 	}
 
+	users := make([]*userGroups, 0, len(groups))
+	for u, r := range groups {
+		users = append(users, &userGroups{ID: u, Groups: r})
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	if output.HasFlag() {
+		err = output.Print(users)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(writer, "ID\t\tGROUPS\n")
 
-	for u, r := range groups {
-		fmt.Fprintf(writer, "%s\t\t%s\n", u, strings.Join(r, ", "))
-		writer.Flush()
+	for _, user := range users {
+		fmt.Fprintf(writer, "%s\t\t%s\n", user.ID, strings.Join(user.Groups, ", "))
 	}
+	writer.Flush()
 }
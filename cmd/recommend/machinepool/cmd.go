@@ -0,0 +1,191 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Number of alternatives shown after the recommended instance type
+const alternatives = 5
+
+var args struct {
+	cpu      float64
+	memory   float64
+	pods     int
+	category string
+	multiAZ  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "machinepool",
+	Aliases: []string{"machine-pool"},
+	Short:   "Recommend an instance type and replica count for a workload",
+	Long: "Recommend the instance type and number of replicas of a machine pool that can run a " +
+		"workload with the fewest nodes. The resources that OpenShift reserves on each node, and " +
+		"the maximum number of pods per node, are taken into account.",
+	Example: `  # Recommend a machine pool for a workload that needs 40 cores, 160 GiB of memory and 300 pods
+  rosa recommend machinepool --cpu=40 --memory=160 --pods=300
+
+  # Recommend a memory optimized machine pool for a multi-AZ cluster
+  rosa recommend machinepool --cpu=16 --memory=512 --category=memory --multi-az`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.Float64Var(
+		&args.cpu,
+		"cpu",
+		0,
+		"Total number of CPU cores requested by the workload.",
+	)
+
+	flags.Float64Var(
+		&args.memory,
+		"memory",
+		0,
+		"Total memory requested by the workload, in GiB.",
+	)
+
+	flags.IntVar(
+		&args.pods,
+		"pods",
+		0,
+		"Total number of pods of the workload.",
+	)
+
+	flags.StringVar(
+		&args.category,
+		"category",
+		"",
+		"Only recommend instance types of this category: 'general', 'memory', 'compute' or "+
+			"'accelerated'. Accelerated instance types are only recommended if requested.",
+	)
+
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Recommend a machine pool for a multi-AZ cluster, with replicas spread evenly across zones.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	if args.cpu < 0 || args.memory < 0 || args.pods < 0 {
+		reporter.Errorf("The CPU cores, memory and pods of the workload must be non-negative")
+		os.Exit(1)
+	}
+	if args.cpu == 0 && args.memory == 0 && args.pods == 0 {
+		reporter.Errorf("Expected at least one of the '--cpu', '--memory' or '--pods' flags")
+		os.Exit(1)
+	}
+	filter := ocm.MachineTypeFilter{
+		MultiAZ: args.multiAZ,
+	}
+	if args.category != "" {
+		category, err := ocm.ParseMachineTypeCategory(args.category)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		filter.Category = category
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	reporter.Debugf("Fetching instance types")
+	machineTypes, err := ocmClient.GetAvailableMachineTypes()
+	if err != nil {
+		reporter.Errorf("Failed to fetch instance types: %v", err)
+		os.Exit(1)
+	}
+	machineTypes = ocm.FilterMachineTypes(machineTypes, filter)
+
+	// Accelerated instance types are much more expensive, so they are only considered when
+	// explicitly requested:
+	if filter.Category == "" {
+		candidates := []*ocm.MachineType{}
+		for _, machineType := range machineTypes {
+			if machineType.MachineType.Category() != ocm.AcceleratedComputing {
+				candidates = append(candidates, machineType)
+			}
+		}
+		machineTypes = candidates
+	}
+
+	recommendations := ocm.RecommendMachineTypes(machineTypes, ocm.Workload{
+		CPU:     args.cpu,
+		Memory:  args.memory * (1 << 30),
+		Pods:    args.pods,
+		MultiAZ: args.multiAZ,
+	})
+	if len(recommendations) == 0 {
+		reporter.Errorf("There are no instance types available that can run the workload")
+		os.Exit(1)
+	}
+
+	recommended := recommendations[0]
+	reporter.Infof("Recommended instance type '%s' with %d replicas",
+		recommended.MachineType.MachineType.ID(), recommended.Replicas)
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\tREPLICAS\n")
+	for i, recommendation := range recommendations {
+		if i > alternatives {
+			break
+		}
+		machineType := recommendation.MachineType.MachineType
+		fmt.Fprintf(writer,
+			"%s\t%s\t%d\t%s\t%d\n",
+			machineType.ID(), machineType.Category(), int(machineType.CPU().Value()),
+			ocm.ByteCountIEC(int(machineType.Memory().Value()), machineType.Memory().Unit()),
+			recommendation.Replicas,
+		)
+	}
+	writer.Flush()
+
+	reporter.Infof("To create the machine pool, run 'rosa create machinepool --cluster=<cluster> "+
+		"--name=<name> --instance-type=%s --replicas=%d'",
+		recommended.MachineType.MachineType.ID(), recommended.Replicas)
+}
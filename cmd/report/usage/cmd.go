@@ -0,0 +1,404 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
+	"github.com/openshift/rosa/pkg/properties"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	filter  string
+	groupBy string
+}
+
+// Cluster attributes that can be used to group the report, in addition to 'tag:<key>' and
+// 'property:<key>'
+var groupByOptions = []string{"cluster", "account", "region", "version", "state"}
+
+var Cmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report the resource usage of clusters",
+	Long: "Report the nodes, instance types and vCPU and socket totals of all clusters, " +
+		"optionally grouped by a cluster attribute or AWS tag. Use '--output csv' or " +
+		"'--output json' to generate a file for billing.",
+	Example: `  # Report the usage of all clusters
+  rosa report usage
+
+  # Generate a CSV report of the usage grouped by the "cost-center" AWS tag
+  rosa report usage --group-by tag:cost-center -o csv > usage.csv
+
+  # Report the usage of the clusters in a region grouped by OpenShift version
+  rosa report usage --filter "region.id = 'us-east-1'" --group-by version -o json`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"Search query used to select the clusters to include, for example \"region.id = 'us-east-1'\".",
+	)
+
+	flags.StringVar(
+		&args.groupBy,
+		"group-by",
+		"cluster",
+		fmt.Sprintf("Attribute used to aggregate the usage. Valid options are ['%s'], 'tag:<key>' "+
+			"to group by an AWS tag and 'property:<key>' to group by a cluster property.",
+			strings.Join(groupByOptions, "','")),
+	)
+	Cmd.RegisterFlagCompletionFunc("group-by", groupByCompletion)
+
+	output.AddFlag(Cmd)
+}
+
+func groupByCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return append(groupByOptions, "tag:", "property:"), cobra.ShellCompDirectiveNoSpace
+}
+
+// clusterUsage contains the usage of a single cluster.
+type clusterUsage struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Account       string  `json:"account"`
+	Region        string  `json:"region"`
+	Version       string  `json:"version"`
+	State         string  `json:"state"`
+	InstanceTypes string  `json:"instance_types"`
+	MasterNodes   int     `json:"master_nodes"`
+	InfraNodes    int     `json:"infra_nodes"`
+	ComputeNodes  int     `json:"compute_nodes"`
+	VCPUs         float64 `json:"vcpus"`
+	Sockets       float64 `json:"sockets"`
+	Created       string  `json:"created"`
+	Expiration    string  `json:"expiration,omitempty"`
+
+	cluster *cmv1.Cluster
+}
+
+// groupUsage contains the usage of all the clusters that have the same value for the attribute
+// selected with '--group-by'.
+type groupUsage struct {
+	Group         string  `json:"group"`
+	Clusters      int     `json:"clusters"`
+	InstanceTypes string  `json:"instance_types"`
+	MasterNodes   int     `json:"master_nodes"`
+	InfraNodes    int     `json:"infra_nodes"`
+	ComputeNodes  int     `json:"compute_nodes"`
+	VCPUs         float64 `json:"vcpus"`
+	Sockets       float64 `json:"sockets"`
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	groupBy := strings.TrimSpace(args.groupBy)
+	if !isValidGroupBy(groupBy) {
+		reporter.Errorf("Invalid value '%s' for --group-by. Valid options are ['%s'], 'tag:<key>' "+
+			"and 'property:<key>'", groupBy, strings.Join(groupByOptions, "','"))
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	reporter.Debugf("Loading clusters")
+	clusters, err := ocmClient.FindClusters(awsCreator, args.filter)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters: %v", err)
+		os.Exit(1)
+	}
+	if len(clusters) == 0 {
+		reporter.Infof("There are no clusters for this AWS account")
+		os.Exit(0)
+	}
+
+	usages := make([]*clusterUsage, 0, len(clusters))
+	for _, cluster := range clusters {
+		usages = append(usages, getClusterUsage(reporter, ocmClient, cluster))
+	}
+
+	if groupBy == "cluster" {
+		if output.HasFlag() {
+			err = output.Print(usages)
+			if err != nil {
+				reporter.Errorf("%s", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "ID\tNAME\tREGION\tINSTANCE TYPES\tMASTER\tINFRA\tCOMPUTE\tVCPUS\tSOCKETS\tCREATED\n")
+		for _, usage := range usages {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%g\t%g\t%s\n",
+				usage.ID, usage.Name, usage.Region, usage.InstanceTypes,
+				usage.MasterNodes, usage.InfraNodes, usage.ComputeNodes,
+				usage.VCPUs, usage.Sockets, usage.Created)
+		}
+		writer.Flush()
+		return
+	}
+
+	groups := groupUsages(usages, groupBy)
+	if output.HasFlag() {
+		err = output.Print(groups)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "%s\tCLUSTERS\tINSTANCE TYPES\tMASTER\tINFRA\tCOMPUTE\tVCPUS\tSOCKETS\n",
+		strings.ToUpper(groupBy))
+	for _, group := range groups {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%d\t%d\t%d\t%g\t%g\n",
+			group.Group, group.Clusters, group.InstanceTypes,
+			group.MasterNodes, group.InfraNodes, group.ComputeNodes,
+			group.VCPUs, group.Sockets)
+	}
+	writer.Flush()
+}
+
+func isValidGroupBy(groupBy string) bool {
+	for _, option := range groupByOptions {
+		if groupBy == option {
+			return true
+		}
+	}
+	for _, prefix := range []string{"tag:", "property:"} {
+		if strings.HasPrefix(groupBy, prefix) && len(groupBy) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func getClusterUsage(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster) *clusterUsage {
+	usage := &clusterUsage{
+		ID:          cluster.ID(),
+		Name:        cluster.Name(),
+		Region:      cluster.Region().ID(),
+		Version:     cluster.OpenshiftVersion(),
+		State:       string(cluster.State()),
+		MasterNodes: cluster.Nodes().Master(),
+		InfraNodes:  cluster.Nodes().Infra(),
+		Created:     cluster.CreationTimestamp().UTC().Format(time.RFC3339),
+		cluster:     cluster,
+	}
+	if !cluster.ExpirationTimestamp().IsZero() {
+		usage.Expiration = cluster.ExpirationTimestamp().UTC().Format(time.RFC3339)
+	}
+	creatorARN, err := arn.Parse(cluster.Properties()[properties.CreatorARN])
+	if err == nil {
+		usage.Account = creatorARN.AccountID
+	}
+
+	instanceTypes := map[string]bool{}
+	if cluster.Nodes().ComputeMachineType().ID() != "" {
+		instanceTypes[cluster.Nodes().ComputeMachineType().ID()] = true
+	}
+	// Until the cluster reports its nodes, count the replicas that it was configured with
+	usage.ComputeNodes = cluster.Nodes().Compute()
+	autoscaled := false
+	if cluster.Nodes().AutoscaleCompute() != nil {
+		usage.ComputeNodes = cluster.Nodes().AutoscaleCompute().MinReplicas()
+		autoscaled = true
+	}
+
+	// Metrics and machine pools are only available once the cluster is ready
+	if cluster.State() == cmv1.ClusterStateReady {
+		machinePools, err := ocmClient.GetMachinePools(cluster.ID())
+		if err != nil {
+			reporter.Warnf("Failed to get machine pools for cluster '%s': %v", cluster.ID(), err)
+		}
+		for _, machinePool := range machinePools {
+			instanceTypes[machinePool.InstanceType()] = true
+			if machinePool.Autoscaling() != nil {
+				usage.ComputeNodes += machinePool.Autoscaling().MinReplicas()
+				autoscaled = true
+			} else {
+				usage.ComputeNodes += machinePool.Replicas()
+			}
+		}
+
+		// Autoscaled clusters can run more nodes than the minimum, so prefer the number of
+		// nodes that the cluster reports
+		nodes, err := ocmClient.GetNodes(cluster.ID())
+		if err != nil {
+			reporter.Warnf("Failed to get nodes for cluster '%s': %v", cluster.ID(), err)
+		}
+		if len(nodes) > 0 {
+			usage.MasterNodes = 0
+			usage.InfraNodes = 0
+			usage.ComputeNodes = 0
+			for _, node := range nodes {
+				switch node.Type() {
+				case cmv1.NodeTypeMaster:
+					usage.MasterNodes += node.Amount()
+				case cmv1.NodeTypeInfra:
+					usage.InfraNodes += node.Amount()
+				case cmv1.NodeTypeCompute:
+					usage.ComputeNodes += node.Amount()
+				}
+			}
+			autoscaled = false
+		}
+
+		cpuTotals, err := ocmClient.GetCPUTotals(cluster.ID())
+		if err != nil {
+			reporter.Warnf("Failed to get vCPU totals for cluster '%s': %v", cluster.ID(), err)
+		}
+		for _, cpuTotal := range cpuTotals {
+			usage.VCPUs += cpuTotal.CPUTotal()
+		}
+
+		socketTotals, err := ocmClient.GetSocketTotals(cluster.ID())
+		if err != nil {
+			reporter.Warnf("Failed to get socket totals for cluster '%s': %v", cluster.ID(), err)
+		}
+		for _, socketTotal := range socketTotals {
+			usage.Sockets += socketTotal.SocketTotal()
+		}
+	}
+
+	if autoscaled {
+		reporter.Warnf("Cluster '%s' doesn't report its nodes, so its autoscaled compute nodes are "+
+			"counted at their minimum number of replicas", cluster.Name())
+	}
+
+	usage.InstanceTypes = joinKeys(instanceTypes)
+	return usage
+}
+
+// getGroup returns the value of the '--group-by' attribute for the cluster.
+func getGroup(usage *clusterUsage, groupBy string) string {
+	var group string
+	switch {
+	case groupBy == "cluster":
+		group = usage.Name
+	case groupBy == "account":
+		group = usage.Account
+	case groupBy == "region":
+		group = usage.Region
+	case groupBy == "version":
+		group = usage.Version
+	case groupBy == "state":
+		group = usage.State
+	case strings.HasPrefix(groupBy, "tag:"):
+		group = usage.cluster.AWS().Tags()[strings.TrimPrefix(groupBy, "tag:")]
+	case strings.HasPrefix(groupBy, "property:"):
+		group = usage.cluster.Properties()[strings.TrimPrefix(groupBy, "property:")]
+	}
+	if group == "" {
+		group = "<none>"
+	}
+	return group
+}
+
+func groupUsages(usages []*clusterUsage, groupBy string) []*groupUsage {
+	groups := map[string]*groupUsage{}
+	instanceTypes := map[string]map[string]bool{}
+	for _, usage := range usages {
+		key := getGroup(usage, groupBy)
+		group, ok := groups[key]
+		if !ok {
+			group = &groupUsage{Group: key}
+			groups[key] = group
+			instanceTypes[key] = map[string]bool{}
+		}
+		group.Clusters++
+		group.MasterNodes += usage.MasterNodes
+		group.InfraNodes += usage.InfraNodes
+		group.ComputeNodes += usage.ComputeNodes
+		group.VCPUs += usage.VCPUs
+		group.Sockets += usage.Sockets
+		for _, instanceType := range strings.Split(usage.InstanceTypes, ",") {
+			if instanceType != "" {
+				instanceTypes[key][instanceType] = true
+			}
+		}
+	}
+
+	result := make([]*groupUsage, 0, len(groups))
+	for key, group := range groups {
+		group.InstanceTypes = joinKeys(instanceTypes[key])
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Group < result[j].Group
+	})
+	return result
+}
+
+func joinKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
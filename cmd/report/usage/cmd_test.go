@@ -0,0 +1,125 @@
+package usage_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/cmd/report/usage"
+)
+
+func clusterUsage(name string, region string, tags map[string]string, properties map[string]string,
+	instanceTypes string, computeNodes int, vcpus float64) *usage.ClusterUsage {
+	cluster, err := cmv1.NewCluster().
+		Name(name).
+		AWS(cmv1.NewAWS().Tags(tags)).
+		Properties(properties).
+		Build()
+	Expect(err).ToNot(HaveOccurred())
+	item := &usage.ClusterUsage{
+		Name:          name,
+		Region:        region,
+		InstanceTypes: instanceTypes,
+		MasterNodes:   3,
+		InfraNodes:    2,
+		ComputeNodes:  computeNodes,
+		VCPUs:         vcpus,
+		Sockets:       vcpus / 2,
+	}
+	return item.SetCluster(cluster)
+}
+
+var _ = Describe("Usage", func() {
+	DescribeTable("IsValidGroupBy",
+		func(groupBy string, expected bool) {
+			Expect(usage.IsValidGroupBy(groupBy)).To(Equal(expected))
+		},
+		Entry("cluster", "cluster", true),
+		Entry("region", "region", true),
+		Entry("tag", "tag:cost-center", true),
+		Entry("property", "property:rosa_creator_arn", true),
+		Entry("tag without key", "tag:", false),
+		Entry("property without key", "property:", false),
+		Entry("unknown attribute", "owner", false),
+		Entry("empty", "", false),
+	)
+
+	Context("GroupUsages", func() {
+		var usages []*usage.ClusterUsage
+
+		BeforeEach(func() {
+			usages = []*usage.ClusterUsage{
+				clusterUsage("prod-1", "us-east-1", map[string]string{"cost-center": "sales"},
+					map[string]string{"team": "a"}, "m5.xlarge", 3, 28),
+				clusterUsage("prod-2", "us-west-2", map[string]string{"cost-center": "sales"},
+					map[string]string{}, "m5.xlarge,r5.2xlarge", 6, 52),
+				clusterUsage("dev", "us-east-1", map[string]string{},
+					map[string]string{"team": "a"}, "m5.xlarge", 2, 24),
+			}
+		})
+
+		groups := func(groupBy string) map[string]*usage.GroupUsage {
+			result := map[string]*usage.GroupUsage{}
+			for _, group := range usage.GroupUsages(usages, groupBy) {
+				result[group.Group] = group
+			}
+			return result
+		}
+
+		It("adds up the usage of the clusters in each group", func() {
+			Expect(usage.GroupUsages(usages, "region")).To(Equal([]*usage.GroupUsage{
+				{
+					Group:         "us-east-1",
+					Clusters:      2,
+					InstanceTypes: "m5.xlarge",
+					MasterNodes:   6,
+					InfraNodes:    4,
+					ComputeNodes:  5,
+					VCPUs:         52,
+					Sockets:       26,
+				},
+				{
+					Group:         "us-west-2",
+					Clusters:      1,
+					InstanceTypes: "m5.xlarge,r5.2xlarge",
+					MasterNodes:   3,
+					InfraNodes:    2,
+					ComputeNodes:  6,
+					VCPUs:         52,
+					Sockets:       26,
+				},
+			}))
+		})
+
+		It("groups by AWS tag", func() {
+			result := groups("tag:cost-center")
+			Expect(result).To(HaveLen(2))
+			Expect(result["sales"].Clusters).To(Equal(2))
+			Expect(result["sales"].VCPUs).To(Equal(80.0))
+			Expect(result["<none>"].Clusters).To(Equal(1))
+		})
+
+		It("groups by cluster property", func() {
+			result := groups("property:team")
+			Expect(result).To(HaveLen(2))
+			Expect(result["a"].Clusters).To(Equal(2))
+			Expect(result["a"].ComputeNodes).To(Equal(5))
+			Expect(result["<none>"].Clusters).To(Equal(1))
+		})
+
+		It("groups by cluster", func() {
+			result := groups("cluster")
+			Expect(result).To(HaveLen(3))
+			Expect(result["prod-2"].InstanceTypes).To(Equal("m5.xlarge,r5.2xlarge"))
+		})
+
+		It("puts all the clusters in one group for unknown keys", func() {
+			result := groups("tag:missing")
+			Expect(result).To(HaveLen(1))
+			Expect(result["<none>"].Clusters).To(Equal(3))
+			Expect(result["<none>"].MasterNodes).To(Equal(9))
+		})
+	})
+})
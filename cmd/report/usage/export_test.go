@@ -0,0 +1,20 @@
+package usage
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Unexported types and functions used by the tests of the package
+type ClusterUsage = clusterUsage
+type GroupUsage = groupUsage
+
+var (
+	GroupUsages    = groupUsages
+	IsValidGroupBy = isValidGroupBy
+)
+
+// SetCluster sets the cluster that the tags and properties used for grouping are read from.
+func (u *clusterUsage) SetCluster(cluster *cmv1.Cluster) *clusterUsage {
+	u.cluster = cluster
+	return u
+}
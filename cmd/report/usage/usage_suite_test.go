@@ -0,0 +1,13 @@
+package usage_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestUsage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Report Usage Suite")
+}
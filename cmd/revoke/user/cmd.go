@@ -19,7 +19,10 @@ package user
 import (
 	"fmt"
 	"os"
+	"text/tabwriter"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/aws"
@@ -30,8 +33,10 @@ import (
 )
 
 var args struct {
-	clusterKey string
-	username   string
+	clusterKey  string
+	username    string
+	allClusters bool
+	filter      string
 }
 
 var Cmd = &cobra.Command{
@@ -43,9 +48,16 @@ var Cmd = &cobra.Command{
   rosa revoke user cluster-admins --user=myusername --cluster=mycluster
 
   # Revoke dedicated-admin role from a user
-  rosa revoke user dedicated-admins --user=myusername --cluster=mycluster`,
+  rosa revoke user dedicated-admins --user=myusername --cluster=mycluster
+
+  # Revoke all roles from a user on every cluster
+  rosa revoke user myusername --all-clusters`,
 	Run: run,
 	Args: func(_ *cobra.Command, argv []string) error {
+		// With all clusters the argument is the username, which can also be given with '--user'
+		if args.allClusters && len(argv) <= 1 {
+			return nil
+		}
 		if len(argv) != 1 {
 			return fmt.Errorf(
 				"Expected exactly one command line argument containing the name " +
@@ -67,9 +79,8 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID of the cluster to delete the users from (required).",
+		"Name or ID of the cluster to delete the users from (required unless --all-clusters is used).",
 	)
-	Cmd.MarkFlagRequired("cluster")
 
 	flags.StringVarP(
 		&args.username,
@@ -78,13 +89,40 @@ func init() {
 		"",
 		"Username to revoke the role from (required).",
 	)
-	Cmd.MarkFlagRequired("user")
+
+	flags.BoolVar(
+		&args.allClusters,
+		"all-clusters",
+		false,
+		"Revoke all roles from the user on every cluster of the account.",
+	)
+
+	flags.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"With --all-clusters, only revoke from the clusters that match this search query, "+
+			"for example \"name like 'prod%'\".",
+	)
 }
 
 func run(_ *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	if args.allClusters {
+		runAllClusters(reporter, logger, argv)
+		return
+	}
+	if args.clusterKey == "" || args.username == "" {
+		reporter.Errorf("Expected the '--cluster' and '--user' flags, or the '--all-clusters' flag")
+		os.Exit(1)
+	}
+	if args.filter != "" {
+		reporter.Errorf("The '--filter' flag can only be used with '--all-clusters'")
+		os.Exit(1)
+	}
+
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
@@ -193,3 +231,149 @@ func run(_ *cobra.Command, argv []string) {
 	}
 	reporter.Infof("Revoked role '%s' from user '%s' on cluster '%s'", role, username, clusterKey)
 }
+
+type revocation struct {
+	cluster string
+	role    string
+	result  string
+}
+
+func runAllClusters(reporter *rprtr.Object, logger *logrus.Logger, argv []string) {
+	username := args.username
+	if len(argv) == 1 {
+		if username != "" && username != argv[0] {
+			reporter.Errorf("Expected the username either as argument or with the '--user' flag")
+			os.Exit(1)
+		}
+		username = argv[0]
+	}
+	if username == "" {
+		reporter.Errorf("Expected the username of the user to revoke the roles from")
+		os.Exit(1)
+	}
+	if !ocm.IsValidUsername(username) {
+		reporter.Errorf(
+			"Username '%s' isn't valid: it must contain only letters, digits, dashes and underscores",
+			username,
+		)
+		os.Exit(1)
+	}
+	if username == "cluster-admin" {
+		reporter.Errorf("Username 'cluster-admin' is not allowed")
+		os.Exit(1)
+	}
+	if args.clusterKey != "" {
+		reporter.Errorf("The '--cluster' flag can't be used with '--all-clusters'")
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	reporter.Debugf("Loading clusters")
+	clusters, err := ocmClient.FindClusters(awsCreator, args.filter)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters: %v", err)
+		os.Exit(1)
+	}
+
+	// Find the roles of the user before asking for confirmation:
+	type membership struct {
+		cluster *cmv1.Cluster
+		role    string
+	}
+	memberships := []membership{}
+	revocations := []*revocation{}
+	for _, cluster := range clusters {
+		// The roles of the user can't be checked on clusters that aren't ready, so report them
+		// as skipped since the user may still have roles there:
+		if cluster.State() != cmv1.ClusterStateReady {
+			revocations = append(revocations, &revocation{
+				cluster: cluster.Name(),
+				role:    "-",
+				result:  "skipped: cluster is not ready",
+			})
+			continue
+		}
+		for _, role := range validRoles {
+			reporter.Debugf("Loading '%s' users for cluster '%s'", role, cluster.Name())
+			user, err := ocmClient.GetUser(cluster.ID(), role, username)
+			if err != nil {
+				revocations = append(revocations, &revocation{
+					cluster: cluster.Name(),
+					role:    role,
+					result:  fmt.Sprintf("failed: %v", err),
+				})
+				continue
+			}
+			if user != nil {
+				memberships = append(memberships, membership{cluster: cluster, role: role})
+			}
+		}
+	}
+
+	if len(memberships) == 0 && len(revocations) == 0 {
+		reporter.Infof("User '%s' has no roles on any of the %d clusters", username, len(clusters))
+		os.Exit(0)
+	}
+
+	if len(memberships) > 0 &&
+		!confirm.Confirm("revoke %d roles from user %s in all clusters", len(memberships), username) {
+		os.Exit(0)
+	}
+
+	for _, m := range memberships {
+		reporter.Debugf("Removing user '%s' from group '%s' in cluster '%s'", username, m.role, m.cluster.Name())
+		r := &revocation{
+			cluster: m.cluster.Name(),
+			role:    m.role,
+			result:  "revoked",
+		}
+		err = ocmClient.DeleteUser(m.cluster.ID(), m.role, username)
+		if err != nil {
+			r.result = fmt.Sprintf("failed: %v", err)
+		}
+		revocations = append(revocations, r)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CLUSTER\tROLE\tRESULT\n")
+	failed := false
+	for _, r := range revocations {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", r.cluster, r.role, r.result)
+		failed = failed || r.result != "revoked"
+	}
+	writer.Flush()
+
+	if failed {
+		reporter.Errorf("Failed to revoke the roles of user '%s' in some of the clusters", username)
+		os.Exit(1)
+	}
+}
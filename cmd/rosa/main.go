@@ -22,27 +22,34 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/cmd/apply"
 	"github.com/openshift/rosa/cmd/completion"
 	"github.com/openshift/rosa/cmd/create"
 	"github.com/openshift/rosa/cmd/describe"
+	"github.com/openshift/rosa/cmd/diff"
 	"github.com/openshift/rosa/cmd/dlt"
 	"github.com/openshift/rosa/cmd/docs"
 	"github.com/openshift/rosa/cmd/download"
 	"github.com/openshift/rosa/cmd/edit"
+	"github.com/openshift/rosa/cmd/export"
 	"github.com/openshift/rosa/cmd/grant"
 	"github.com/openshift/rosa/cmd/hibernate"
+	"github.com/openshift/rosa/cmd/imprt"
 	"github.com/openshift/rosa/cmd/initialize"
 	"github.com/openshift/rosa/cmd/install"
 	"github.com/openshift/rosa/cmd/list"
 	"github.com/openshift/rosa/cmd/login"
 	"github.com/openshift/rosa/cmd/logout"
 	"github.com/openshift/rosa/cmd/logs"
+	"github.com/openshift/rosa/cmd/recommend"
+	"github.com/openshift/rosa/cmd/report"
 	"github.com/openshift/rosa/cmd/resume"
 	"github.com/openshift/rosa/cmd/revoke"
 	"github.com/openshift/rosa/cmd/uninstall"
 	"github.com/openshift/rosa/cmd/upgrade"
 	"github.com/openshift/rosa/cmd/verify"
 	"github.com/openshift/rosa/cmd/version"
+	"github.com/openshift/rosa/cmd/watch"
 	"github.com/openshift/rosa/cmd/whoami"
 	"github.com/openshift/rosa/pkg/arguments"
 )
@@ -59,25 +66,32 @@ func init() {
 	arguments.AddDebugFlag(fs)
 
 	// Register the subcommands:
+	root.AddCommand(apply.Cmd)
 	root.AddCommand(completion.Cmd)
 	root.AddCommand(create.Cmd)
 	root.AddCommand(describe.Cmd)
+	root.AddCommand(diff.Cmd)
 	root.AddCommand(dlt.Cmd)
 	root.AddCommand(docs.Cmd)
 	root.AddCommand(download.Cmd)
 	root.AddCommand(edit.Cmd)
+	root.AddCommand(export.Cmd)
 	root.AddCommand(grant.Cmd)
+	root.AddCommand(imprt.Cmd)
 	root.AddCommand(list.Cmd)
 	root.AddCommand(initialize.Cmd)
 	root.AddCommand(install.Cmd)
 	root.AddCommand(login.Cmd)
 	root.AddCommand(logout.Cmd)
 	root.AddCommand(logs.Cmd)
+	root.AddCommand(recommend.Cmd)
+	root.AddCommand(report.Cmd)
 	root.AddCommand(revoke.Cmd)
 	root.AddCommand(uninstall.Cmd)
 	root.AddCommand(upgrade.Cmd)
 	root.AddCommand(verify.Cmd)
 	root.AddCommand(version.Cmd)
+	root.AddCommand(watch.Cmd)
 	root.AddCommand(whoami.Cmd)
 	root.AddCommand(hibernate.Cmd)
 	root.AddCommand(resume.Cmd)
@@ -19,7 +19,6 @@ package cluster
 import (
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -36,20 +35,14 @@ import (
 var args struct {
 	clusterKey           string
 	version              string
+	to                   string
 	scheduleDate         string
 	scheduleTime         string
 	nodeDrainGracePeriod string
 }
 
-var nodeDrainOptions = []string{
-	"15 minutes",
-	"30 minutes",
-	"45 minutes",
-	"1 hour",
-	"2 hours",
-	"4 hours",
-	"8 hours",
-}
+// Time to wait between checks of the upgrade state when upgrading through multiple versions
+const watchInterval = 1 * time.Minute
 
 var Cmd = &cobra.Command{
 	Use:   "cluster",
@@ -59,7 +52,10 @@ var Cmd = &cobra.Command{
   rosa upgrade cluster --cluster=mycluster --interactive
 
   # Schedule a cluster upgrade within the hour
-  rosa upgade cluster -c mycluster --version 4.5.20`,
+  rosa upgade cluster -c mycluster --version 4.5.20
+
+  # Upgrade a cluster through all the intermediate versions needed to reach 4.8.2
+  rosa upgrade cluster -c mycluster --to 4.8.2`,
 	Run: run,
 }
 
@@ -83,6 +79,14 @@ func init() {
 		"Version of OpenShift that the cluster will be upgraded to",
 	)
 
+	flags.StringVar(
+		&args.to,
+		"to",
+		"",
+		"Version of OpenShift that the cluster will be upgraded to through the shortest sequence of "+
+			"upgrades. Each upgrade is scheduled once the previous one has completed.",
+	)
+
 	flags.StringVar(
 		&args.scheduleDate,
 		"schedule-date",
@@ -104,7 +108,7 @@ func init() {
 		fmt.Sprintf("You may set a grace period for how long Pod Disruption Budget-protected workloads will be "+
 			"respected during upgrades.\nAfter this grace period, any workloads protected by Pod Disruption "+
 			"Budgets that have not been successfully drained from a node will be forcibly evicted.\nValid "+
-			"options are ['%s']", strings.Join(nodeDrainOptions, "','")),
+			"options are ['%s']", strings.Join(ocm.NodeDrainOptions, "','")),
 	)
 }
 
@@ -186,6 +190,24 @@ func run(cmd *cobra.Command, _ []string) {
 	scheduleDate := args.scheduleDate
 	scheduleTime := args.scheduleTime
 
+	// Determine the intermediate versions needed to reach the target version
+	var path []string
+	if args.to != "" {
+		if version != "" {
+			reporter.Errorf("Flags '--version' and '--to' are mutually exclusive")
+			os.Exit(1)
+		}
+		reporter.Debugf("Loading upgrade path for cluster '%s' to version %s", clusterKey, args.to)
+		path, err = ocmClient.GetUpgradePath(cluster, args.to)
+		if err != nil {
+			reporter.Errorf("Failed to get upgrade path for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		version = path[0]
+		reporter.Infof("Cluster '%s' will be upgraded to version %s through versions %s",
+			clusterKey, args.to, strings.Join(path, ", "))
+	}
+
 	availableUpgrades, err := ocmClient.GetAvailableUpgrades(ocm.GetVersionID(cluster))
 	if err != nil {
 		reporter.Errorf("Failed to find available upgrades: %v", err)
@@ -196,7 +218,7 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(0)
 	}
 
-	if version == "" || interactive.Enabled() {
+	if version == "" || (interactive.Enabled() && len(path) == 0) {
 		if version == "" {
 			version = availableUpgrades[0]
 		}
@@ -299,23 +321,8 @@ func run(cmd *cobra.Command, _ []string) {
 		Version(version).
 		NextRun(nextRun)
 
-	nodeDrainGracePeriod := ""
 	// Determine if the cluster already has a node drain grace period set and use that as the default
-	nd := cluster.NodeDrainGracePeriod()
-	if _, ok := nd.GetValue(); ok {
-		// Convert larger times to hours, since the API only stores minutes
-		val := int(nd.Value())
-		unit := nd.Unit()
-		if val >= 60 {
-			val = val / 60
-			if val == 1 {
-				unit = "hour"
-			} else {
-				unit = "hours"
-			}
-		}
-		nodeDrainGracePeriod = fmt.Sprintf("%d %s", val, unit)
-	}
+	nodeDrainGracePeriod := ocm.GetNodeDrainGracePeriod(cluster)
 	// If node drain grace period is not set, or the user sent it as a CLI argument, use that instead
 	if nodeDrainGracePeriod == "" || cmd.Flags().Changed("node-drain-grace-period") {
 		nodeDrainGracePeriod = args.nodeDrainGracePeriod
@@ -324,7 +331,7 @@ func run(cmd *cobra.Command, _ []string) {
 		nodeDrainGracePeriod, err = interactive.GetOption(interactive.Input{
 			Question: "Node draining",
 			Help:     cmd.Flags().Lookup("node-drain-grace-period").Usage,
-			Options:  nodeDrainOptions,
+			Options:  ocm.NodeDrainOptions,
 			Default:  nodeDrainGracePeriod,
 			Required: true,
 		})
@@ -333,27 +340,11 @@ func run(cmd *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 	}
-	isValidNodeDrainGracePeriod := false
-	for _, nodeDrainOption := range nodeDrainOptions {
-		if nodeDrainGracePeriod == nodeDrainOption {
-			isValidNodeDrainGracePeriod = true
-			break
-		}
-	}
-	if !isValidNodeDrainGracePeriod {
-		reporter.Errorf("Expected a valid node drain grace period. Options are [%s]",
-			strings.Join(nodeDrainOptions, ", "))
-		os.Exit(1)
-	}
-	nodeDrainParsed := strings.Split(nodeDrainGracePeriod, " ")
-	nodeDrainValue, err := strconv.ParseFloat(nodeDrainParsed[0], 64)
+	nodeDrainValue, err := ocm.ParseNodeDrainGracePeriod(nodeDrainGracePeriod)
 	if err != nil {
-		reporter.Errorf("Expected a valid node drain grace period: %s", err)
+		reporter.Errorf("%s", err)
 		os.Exit(1)
 	}
-	if nodeDrainParsed[1] == "hours" || nodeDrainParsed[1] == "hour" {
-		nodeDrainValue = nodeDrainValue * 60
-	}
 
 	clusterSpec := ocm.Spec{
 		NodeDrainGracePeriodInMinutes: nodeDrainValue,
@@ -378,4 +369,64 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 
 	reporter.Infof("Upgrade successfully scheduled for cluster '%s'", clusterKey)
+
+	// Schedule the rest of the upgrades once the previous one has completed
+	for i := 1; i < len(path); i++ {
+		waitForUpgrade(reporter, ocmClient, cluster, version)
+		version = path[i]
+
+		upgradePolicy, err = cmv1.NewUpgradePolicy().
+			ScheduleType("manual").
+			Version(version).
+			NextRun(time.Now().UTC().Add(time.Minute * 10)).
+			Build()
+		if err != nil {
+			reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+
+		err = ocmClient.ScheduleUpgrade(cluster.ID(), upgradePolicy)
+		if err != nil {
+			reporter.Errorf("Failed to schedule upgrade for cluster '%s' to version %s: %v",
+				clusterKey, version, err)
+			os.Exit(1)
+		}
+		reporter.Infof("Upgrade to version %s successfully scheduled for cluster '%s'", version, clusterKey)
+	}
+	if len(path) > 1 {
+		waitForUpgrade(reporter, ocmClient, cluster, version)
+	}
+}
+
+// waitForUpgrade blocks until the upgrade of the cluster to the given version has completed,
+// exiting if it fails or gets cancelled.
+func waitForUpgrade(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster, version string) {
+	reporter.Infof("Waiting for cluster '%s' to be upgraded to version %s", cluster.Name(), version)
+	lastState := cmv1.UpgradePolicyStateValueScheduled
+	watch := ocmClient.WatchUpgrade(cluster.ID(), version)
+	for {
+		time.Sleep(watchInterval)
+		upgradeState, err := watch.State()
+		if err != nil {
+			reporter.Errorf("Failed to get upgrade state for cluster '%s': %v", cluster.Name(), err)
+			os.Exit(1)
+		}
+		switch upgradeState.Value() {
+		case cmv1.UpgradePolicyStateValueCompleted:
+			reporter.Infof("Cluster '%s' has been upgraded to version %s", cluster.Name(), version)
+			return
+		case cmv1.UpgradePolicyStateValueFailed:
+			reporter.Errorf("Upgrade of cluster '%s' to version %s failed: %s",
+				cluster.Name(), version, upgradeState.Description())
+			os.Exit(1)
+		case cmv1.UpgradePolicyStateValueCancelled:
+			reporter.Errorf("Upgrade of cluster '%s' to version %s was cancelled", cluster.Name(), version)
+			os.Exit(1)
+		}
+		if upgradeState.Value() != lastState {
+			reporter.Infof("Upgrade of cluster '%s' to version %s is %s",
+				cluster.Name(), version, upgradeState.Value())
+			lastState = upgradeState.Value()
+		}
+	}
 }
@@ -0,0 +1,393 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusters
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Time to wait between checks of the upgrade state of the clusters in a wave
+const watchInterval = 1 * time.Minute
+
+var args struct {
+	filter  string
+	version string
+	waves   int
+	canary  int
+	waveGap time.Duration
+	dryRun  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "clusters",
+	Short: "Upgrade multiple clusters",
+	Long: "Upgrade all the clusters matching a search filter to a new version. Clusters are upgraded " +
+		"in ordered waves, starting with a canary wave, and no further waves are scheduled if an " +
+		"upgrade in an earlier wave fails.",
+	Example: `  # Show the upgrade plan for all clusters whose name starts with "prod"
+  rosa upgrade clusters --filter "name like 'prod%'" --version 4.7.19 --waves 3 --dry-run
+
+  # Upgrade the clusters in 3 waves, starting with 2 canary clusters and waiting
+  # 2 hours between waves
+  rosa upgrade clusters --filter "name like 'prod%'" --version 4.7.19 --waves 3 --canary 2 --wave-gap 2h`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"Search query used to select the clusters to upgrade, using the OCM search syntax, "+
+			"for example \"name like 'prod%'\". Only clusters in the current AWS account are considered.",
+	)
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift that the clusters will be upgraded to (required)",
+	)
+	Cmd.MarkFlagRequired("version")
+
+	flags.IntVar(
+		&args.waves,
+		"waves",
+		1,
+		"Number of waves to split the clusters into. The first wave only contains the canary clusters.",
+	)
+
+	flags.IntVar(
+		&args.canary,
+		"canary",
+		1,
+		"Number of clusters in the first wave when upgrading in more than one wave.",
+	)
+
+	flags.DurationVar(
+		&args.waveGap,
+		"wave-gap",
+		30*time.Minute,
+		"Time to wait after a wave has been successfully upgraded before scheduling the next one.",
+	)
+
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Show the upgrade plan without scheduling any upgrade.",
+	)
+
+	confirm.AddFlag(flags)
+}
+
+// result tracks the outcome of the upgrade of a single cluster.
+type result struct {
+	cluster *cmv1.Cluster
+	wave    int
+	state   string
+	watch   *ocm.UpgradeWatch
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	version := args.version
+	if args.waves < 1 {
+		reporter.Errorf("Expected at least one wave")
+		os.Exit(1)
+	}
+	if args.canary < 1 {
+		reporter.Errorf("Expected at least one canary cluster")
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	reporter.Debugf("Loading clusters matching '%s'", args.filter)
+	clusters, err := ocmClient.FindClusters(awsCreator, args.filter)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters: %v", err)
+		os.Exit(1)
+	}
+	if len(clusters) == 0 {
+		reporter.Warnf("There are no clusters matching the filter")
+		os.Exit(0)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name() < clusters[j].Name()
+	})
+
+	// Check which clusters can be upgraded to the target version
+	var eligible []*cmv1.Cluster
+	var skipped []*result
+	for _, cluster := range clusters {
+		reason, err := checkCluster(ocmClient, cluster, version)
+		if err != nil {
+			reporter.Errorf("Failed to check cluster '%s': %v", cluster.Name(), err)
+			os.Exit(1)
+		}
+		if reason != "" {
+			skipped = append(skipped, &result{cluster: cluster, state: reason})
+			continue
+		}
+		eligible = append(eligible, cluster)
+	}
+
+	waves := buildWaves(eligible, args.waves, args.canary)
+
+	// Print the upgrade plan
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "WAVE\tID\tNAME\tVERSION\tNOTES\n")
+	for i, wave := range waves {
+		for _, cluster := range wave {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\n",
+				i+1, cluster.ID(), cluster.Name(), cluster.OpenshiftVersion(),
+				fmt.Sprintf("upgrade to %s", version))
+		}
+	}
+	for _, skip := range skipped {
+		fmt.Fprintf(writer, "-\t%s\t%s\t%s\t%s\n",
+			skip.cluster.ID(), skip.cluster.Name(), skip.cluster.OpenshiftVersion(), skip.state)
+	}
+	writer.Flush()
+	fmt.Println()
+
+	if len(eligible) == 0 {
+		reporter.Warnf("There are no clusters that can be upgraded to version %s", version)
+		os.Exit(0)
+	}
+
+	if args.dryRun {
+		reporter.Infof("Upgrading %d clusters in %d waves should succeed. "+
+			"Run without the '--dry-run' flag to schedule the upgrades.", len(eligible), len(waves))
+		os.Exit(0)
+	}
+
+	if !confirm.Confirm("upgrade %d clusters to version %s in %d waves", len(eligible), version, len(waves)) {
+		os.Exit(0)
+	}
+
+	var results []*result
+	failed := false
+	for i, wave := range waves {
+		var waveResults []*result
+		for _, cluster := range wave {
+			waveResults = append(waveResults, &result{cluster: cluster, wave: i + 1})
+		}
+		results = append(results, waveResults...)
+
+		if failed {
+			for _, r := range waveResults {
+				r.state = "not scheduled"
+			}
+			continue
+		}
+
+		if i > 0 {
+			reporter.Infof("Waiting %s before scheduling wave %d", args.waveGap, i+1)
+			time.Sleep(args.waveGap)
+		}
+
+		reporter.Infof("Scheduling upgrade of wave %d with %d clusters", i+1, len(wave))
+		failed = !runWave(reporter, ocmClient, waveResults, version)
+	}
+
+	// Print the summary report
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "WAVE\tID\tNAME\tRESULT\n")
+	for _, r := range results {
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", r.wave, r.cluster.ID(), r.cluster.Name(), r.state)
+	}
+	writer.Flush()
+
+	if failed {
+		reporter.Errorf("Stopped upgrading clusters after a failure in an earlier wave")
+		os.Exit(1)
+	}
+	reporter.Infof("Successfully upgraded %d clusters to version %s", len(eligible), version)
+}
+
+// checkCluster returns the reason why the given cluster can't be upgraded to the given version,
+// or an empty string if it can be upgraded.
+func checkCluster(ocmClient *ocm.Client, cluster *cmv1.Cluster, version string) (string, error) {
+	if cluster.State() != cmv1.ClusterStateReady {
+		return fmt.Sprintf("cluster is %s", cluster.State()), nil
+	}
+	if cluster.OpenshiftVersion() == version {
+		return "already at target version", nil
+	}
+	scheduledUpgrade, upgradeState, err := ocmClient.GetScheduledUpgrade(cluster.ID())
+	if err != nil {
+		return "", err
+	}
+	if scheduledUpgrade != nil {
+		return fmt.Sprintf("upgrade to %s already %s", scheduledUpgrade.Version(), upgradeState.Value()), nil
+	}
+	availableUpgrades, err := ocmClient.GetAvailableUpgrades(ocm.GetVersionID(cluster))
+	if err != nil {
+		return "", err
+	}
+	for _, availableUpgrade := range availableUpgrades {
+		if availableUpgrade == version {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("no upgrade path to %s", version), nil
+}
+
+// buildWaves splits the clusters into the given number of waves. When there is more than one
+// wave the first one only contains the canary clusters, and the rest are spread evenly across
+// the remaining waves.
+func buildWaves(clusters []*cmv1.Cluster, count int, canary int) [][]*cmv1.Cluster {
+	if len(clusters) == 0 {
+		return nil
+	}
+	if count <= 1 || canary < 1 || len(clusters) <= canary {
+		return [][]*cmv1.Cluster{clusters}
+	}
+	waves := [][]*cmv1.Cluster{clusters[:canary]}
+	rest := clusters[canary:]
+	count--
+	if count > len(rest) {
+		count = len(rest)
+	}
+	size := len(rest) / count
+	extra := len(rest) % count
+	for i := 0; i < count; i++ {
+		n := size
+		if i < extra {
+			n++
+		}
+		waves = append(waves, rest[:n])
+		rest = rest[n:]
+	}
+	return waves
+}
+
+// runWave schedules the upgrade of all the clusters in the wave and waits for them to finish.
+// Returns false if any of the upgrades failed.
+func runWave(reporter *rprtr.Object, ocmClient *ocm.Client, results []*result, version string) bool {
+	nextRun := time.Now().UTC().Add(time.Minute * 10)
+	success := true
+	pending := 0
+	for _, r := range results {
+		upgradePolicy, err := cmv1.NewUpgradePolicy().
+			ScheduleType("manual").
+			Version(version).
+			NextRun(nextRun).
+			Build()
+		if err == nil {
+			err = ocmClient.ScheduleUpgrade(r.cluster.ID(), upgradePolicy)
+		}
+		if err != nil {
+			reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", r.cluster.Name(), err)
+			r.state = "failed to schedule"
+			success = false
+			continue
+		}
+		reporter.Infof("Upgrade successfully scheduled for cluster '%s'", r.cluster.Name())
+		r.state = string(cmv1.UpgradePolicyStateValueScheduled)
+		r.watch = ocmClient.WatchUpgrade(r.cluster.ID(), version)
+		pending++
+	}
+
+	for pending > 0 {
+		time.Sleep(watchInterval)
+		for _, r := range results {
+			if !isPending(r.state) {
+				continue
+			}
+			upgradeState, err := r.watch.State()
+			if err != nil {
+				reporter.Warnf("Failed to get upgrade state for cluster '%s': %v", r.cluster.Name(), err)
+				continue
+			}
+			state := string(upgradeState.Value())
+			if state == r.state {
+				continue
+			}
+			reporter.Infof("Upgrade of cluster '%s' is %s", r.cluster.Name(), state)
+			r.state = state
+			if isPending(state) {
+				continue
+			}
+			pending--
+			if upgradeState.Value() != cmv1.UpgradePolicyStateValueCompleted {
+				success = false
+			}
+		}
+	}
+
+	return success
+}
+
+func isPending(state string) bool {
+	switch cmv1.UpgradePolicyStateValue(state) {
+	case cmv1.UpgradePolicyStateValuePending,
+		cmv1.UpgradePolicyStateValueScheduled,
+		cmv1.UpgradePolicyStateValueStarted,
+		cmv1.UpgradePolicyStateValueDelayed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package clusters_test
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/cmd/upgrade/clusters"
+)
+
+// waveSizes splits the given number of clusters into waves and returns the size of each wave,
+// checking that every cluster is in exactly one wave and that the order is kept.
+func waveSizes(total int, count int, canary int) []int {
+	list := []*cmv1.Cluster{}
+	for i := 0; i < total; i++ {
+		cluster, err := cmv1.NewCluster().ID(fmt.Sprintf("cluster-%02d", i)).Build()
+		Expect(err).ToNot(HaveOccurred())
+		list = append(list, cluster)
+	}
+	sizes := []int{}
+	ids := []string{}
+	for _, wave := range clusters.BuildWaves(list, count, canary) {
+		sizes = append(sizes, len(wave))
+		for _, cluster := range wave {
+			ids = append(ids, cluster.ID())
+		}
+	}
+	expected := []string{}
+	for _, cluster := range list {
+		expected = append(expected, cluster.ID())
+	}
+	Expect(ids).To(Equal(expected))
+	return sizes
+}
+
+var _ = Describe("BuildWaves", func() {
+	DescribeTable("splits the clusters into waves",
+		func(total int, count int, canary int, expected []int) {
+			Expect(waveSizes(total, count, canary)).To(Equal(expected))
+		},
+		Entry("no clusters", 0, 3, 1, []int{}),
+		Entry("a single wave", 5, 1, 1, []int{5}),
+		Entry("no waves", 5, 0, 1, []int{5}),
+		Entry("canary larger than the clusters", 3, 3, 5, []int{3}),
+		Entry("canary as large as the clusters", 3, 3, 3, []int{3}),
+		Entry("even split", 7, 4, 1, []int{1, 2, 2, 2}),
+		Entry("uneven split", 9, 3, 2, []int{2, 4, 3}),
+		Entry("more waves than clusters", 4, 6, 1, []int{1, 1, 1, 1}),
+	)
+})
@@ -0,0 +1,4 @@
+package clusters
+
+// Unexported functions used by the tests of the package
+var BuildWaves = buildWaves
@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/ldap"
+	"github.com/openshift/rosa/pkg/logging"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	idpType string
+	user    string
+	caPath  string
+	ldap    ldap.Flags
+}
+
+var validIdps = []string{"ldap"}
+
+var Cmd = &cobra.Command{
+	Use:   "idp",
+	Short: "Verify an identity provider configuration",
+	Long: "Verify that an identity provider configuration works before adding it to a cluster. " +
+		"For LDAP this connects to the server, binds, looks up a sample user and reports which " +
+		"of the identity attributes resolve.",
+	Example: `  # Verify an LDAP configuration with the user "alice"
+  rosa verify idp --type ldap --url "ldap://ldap.example.com/ou=users,dc=example,dc=com?uid" \
+    --bind-dn "cn=reader,dc=example,dc=com" --bind-password secret --user alice`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.idpType,
+		"type",
+		"t",
+		"",
+		fmt.Sprintf("Type of identity provider. Options are %s\n", validIdps),
+	)
+	Cmd.MarkFlagRequired("type")
+	flags.StringVar(
+		&args.user,
+		"user",
+		"",
+		"Username of a sample user to look up (required).",
+	)
+	Cmd.MarkFlagRequired("user")
+	flags.StringVar(
+		&args.caPath,
+		"ca",
+		"",
+		"Path to PEM-encoded certificate file to use when making requests to the server.\n",
+	)
+
+	// LDAP
+	ldap.AddFlags(flags, &args.ldap)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logging.CreateLoggerOrExit(reporter)
+
+	if args.idpType != "ldap" {
+		reporter.Errorf("Expected a valid IDP type. Options are %s", validIdps)
+		os.Exit(1)
+	}
+	if args.ldap.URL == "" {
+		reporter.Errorf("Expected an LDAP URL, use the '--url' flag")
+		os.Exit(1)
+	}
+
+	ca := ""
+	if args.caPath != "" {
+		cert, err := ioutil.ReadFile(args.caPath)
+		if err != nil {
+			reporter.Errorf("Expected a valid certificate bundle: %s", err)
+			os.Exit(1)
+		}
+		ca = string(cert)
+	}
+
+	reporter.Infof("Looking up user '%s' on LDAP server...", args.user)
+	result, err := ldap.Verify(ldap.Config{
+		URL:                args.ldap.URL,
+		Insecure:           args.ldap.Insecure,
+		CA:                 ca,
+		BindDN:             args.ldap.BindDN,
+		BindPassword:       args.ldap.BindPassword,
+		IDs:                ldap.SplitAttributes(args.ldap.IDs),
+		PreferredUsernames: ldap.SplitAttributes(args.ldap.PreferredUsernames),
+		Names:              ldap.SplitAttributes(args.ldap.Names),
+		Emails:             ldap.SplitAttributes(args.ldap.Emails),
+	}, args.user)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	reporter.Infof("Found user '%s' as '%s'", args.user, result.DN)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ATTRIBUTE\tCONFIGURED\tRESOLVED\tVALUE\n")
+	for _, attribute := range result.Attributes {
+		resolved := attribute.Resolved
+		if resolved == "" {
+			resolved = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			attribute.Name,
+			strings.Join(attribute.Configured, ","),
+			resolved,
+			attribute.Value,
+		)
+	}
+	writer.Flush()
+
+	err = result.Validate()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	for _, attribute := range result.Attributes {
+		if len(attribute.Configured) > 0 && attribute.Resolved == "" {
+			reporter.Warnf("None of the %s attributes %s resolve for user '%s'",
+				attribute.Name, attribute.Configured, args.user)
+		}
+	}
+	reporter.Infof("LDAP configuration ok")
+}
@@ -0,0 +1,449 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusters
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	describeCluster "github.com/openshift/rosa/cmd/describe/cluster"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// ANSI escape sequences used to draw the dashboard
+const (
+	clearScreen = "\033[H\033[2J"
+	colorReset  = "\033[0m"
+	colorRed    = "\033[1;31m"
+	colorGreen  = "\033[1;32m"
+	colorYellow = "\033[1;33m"
+)
+
+// Number of recent changes shown below the dashboard
+const maxRecentChanges = 10
+
+var args struct {
+	filter   string
+	interval time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:     "clusters",
+	Aliases: []string{"cluster"},
+	Short:   "Watch the state of clusters",
+	Long: "Show a dashboard with the state, version, scheduled upgrade and add-ons of clusters " +
+		"that refreshes periodically and highlights changes. Press Enter to select a cluster and " +
+		"see its details. When the output isn't a terminal, a line is printed for each change.",
+	Example: `  # Watch all clusters
+  rosa watch clusters
+
+  # Watch the clusters in a region, refreshing every minute
+  rosa watch clusters --filter "region.id = 'us-east-1'" --interval 1m
+
+  # Log the changes of all clusters to a file
+  rosa watch clusters > changes.log`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.filter,
+		"filter",
+		"",
+		"Search query used to select the clusters to watch, for example \"region.id = 'us-east-1'\".",
+	)
+
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"Time to wait between refreshes.",
+	)
+}
+
+// clusterSnapshot contains the attributes of a cluster that are shown and compared between
+// refreshes.
+type clusterSnapshot struct {
+	ID          string
+	Name        string
+	State       string
+	Description string
+	Version     string
+	Upgrade     string
+	AddOns      string
+}
+
+// change describes a transition of one attribute of a cluster.
+type change struct {
+	time      time.Time
+	cluster   string
+	attribute string
+	from      string
+	to        string
+}
+
+func (c *change) String() string {
+	return fmt.Sprintf("%s %s: %s changed from '%s' to '%s'",
+		c.time.Format("2006-01-02 15:04:05"), c.cluster, c.attribute, c.from, c.to)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	if args.interval < time.Second {
+		reporter.Errorf("Interval must be at least one second")
+		os.Exit(1)
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	tty := isTerminal(os.Stdout) && isTerminal(os.Stdin)
+
+	// Read lines from the terminal in the background, waiting for the dashboard to resume
+	// after each one so that the prompts can read the terminal themselves
+	input := make(chan bool)
+	resume := make(chan bool)
+	if tty {
+		go readLines(input, resume)
+	}
+
+	var previous map[string]*clusterSnapshot
+	recentChanges := []*change{}
+	for {
+		current, err := getSnapshots(reporter, ocmClient, awsCreator)
+		if err != nil {
+			reporter.Errorf("Failed to get clusters: %v", err)
+			os.Exit(1)
+		}
+
+		if previous == nil && !tty {
+			for _, snapshot := range current {
+				fmt.Printf("%s %s: state is '%s', version is '%s'\n",
+					time.Now().Format("2006-01-02 15:04:05"), snapshot.Name,
+					snapshot.State, snapshot.Version)
+			}
+		}
+		changes, highlights := diffSnapshots(previous, current)
+
+		previous = map[string]*clusterSnapshot{}
+		for _, snapshot := range current {
+			previous[snapshot.ID] = snapshot
+		}
+
+		if !tty {
+			for _, c := range changes {
+				fmt.Println(c.String())
+			}
+			time.Sleep(args.interval)
+			continue
+		}
+
+		recentChanges = append(changes, recentChanges...)
+		if len(recentChanges) > maxRecentChanges {
+			recentChanges = recentChanges[:maxRecentChanges]
+		}
+		fmt.Print(clearScreen)
+		fmt.Print(formatDashboard(current, highlights, recentChanges))
+
+		select {
+		case <-time.After(args.interval):
+		case <-input:
+			showDetails(reporter, ocmClient, awsCreator, current)
+			fmt.Print("Press Enter to return to the dashboard")
+			resume <- true
+			<-input
+			resume <- true
+		}
+	}
+}
+
+func getSnapshots(reporter *rprtr.Object, ocmClient *ocm.Client,
+	awsCreator *aws.Creator) ([]*clusterSnapshot, error) {
+	clusters, err := ocmClient.FindClusters(awsCreator, args.filter)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*clusterSnapshot, 0, len(clusters))
+	for _, cluster := range clusters {
+		snapshot := &clusterSnapshot{
+			ID:          cluster.ID(),
+			Name:        cluster.Name(),
+			State:       string(cluster.State()),
+			Description: cluster.Status().Description(),
+			Version:     cluster.OpenshiftVersion(),
+		}
+
+		// Upgrades and add-ons can only be managed once the cluster is ready
+		if cluster.State() == cmv1.ClusterStateReady {
+			scheduledUpgrade, upgradeState, err := ocmClient.GetScheduledUpgrade(cluster.ID())
+			if err != nil {
+				reporter.Debugf("Failed to get scheduled upgrades for cluster '%s': %v", cluster.ID(), err)
+			} else if scheduledUpgrade != nil {
+				snapshot.Upgrade = fmt.Sprintf("%s %s on %s", upgradeState.Value(),
+					scheduledUpgrade.Version(), scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
+			}
+
+			addOnInstallations, err := ocmClient.GetAddOnInstallations(cluster.ID())
+			if err != nil {
+				reporter.Debugf("Failed to get add-ons for cluster '%s': %v", cluster.ID(), err)
+			}
+			addOns := []string{}
+			for _, addOnInstallation := range addOnInstallations {
+				state := addOnInstallation.State()
+				if state == "" {
+					state = cmv1.AddOnInstallationStateInstalling
+				}
+				addOns = append(addOns, fmt.Sprintf("%s=%s", addOnInstallation.Addon().ID(), state))
+			}
+			sort.Strings(addOns)
+			snapshot.AddOns = strings.Join(addOns, ",")
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+func compareSnapshots(old *clusterSnapshot, current *clusterSnapshot) []*change {
+	now := time.Now()
+	attributes := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"state", old.State, current.State},
+		{"status", old.Description, current.Description},
+		{"version", old.Version, current.Version},
+		{"scheduled upgrade", old.Upgrade, current.Upgrade},
+		{"add-ons", old.AddOns, current.AddOns},
+	}
+	changes := []*change{}
+	for _, attribute := range attributes {
+		if attribute.from != attribute.to {
+			changes = append(changes, &change{
+				time:      now,
+				cluster:   current.Name,
+				attribute: attribute.name,
+				from:      attribute.from,
+				to:        attribute.to,
+			})
+		}
+	}
+	return changes
+}
+
+// diffSnapshots returns the changes between the snapshots of the previous refresh and the
+// current ones, and the colors used to highlight the clusters that changed. Clusters that are no
+// longer found are reported as changes but aren't highlighted, as they aren't in the dashboard.
+func diffSnapshots(previous map[string]*clusterSnapshot,
+	current []*clusterSnapshot) ([]*change, map[string]string) {
+	changes := []*change{}
+	highlights := map[string]string{}
+	if previous == nil {
+		return changes, highlights
+	}
+	ids := map[string]bool{}
+	for _, snapshot := range current {
+		ids[snapshot.ID] = true
+		old, ok := previous[snapshot.ID]
+		if !ok {
+			old = &clusterSnapshot{Name: snapshot.Name, State: "not found"}
+		}
+		clusterChanges := compareSnapshots(old, snapshot)
+		if len(clusterChanges) > 0 {
+			highlights[snapshot.ID] = getHighlight(snapshot)
+			changes = append(changes, clusterChanges...)
+		}
+	}
+	gone := []*clusterSnapshot{}
+	for id, old := range previous {
+		if !ids[id] {
+			gone = append(gone, old)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool {
+		return gone[i].Name < gone[j].Name
+	})
+	for _, old := range gone {
+		changes = append(changes, &change{
+			time:      time.Now(),
+			cluster:   old.Name,
+			attribute: "state",
+			from:      old.State,
+			to:        "not found",
+		})
+	}
+	return changes, highlights
+}
+
+// getHighlight returns the color used for a cluster that changed since the last refresh.
+func getHighlight(snapshot *clusterSnapshot) string {
+	switch cmv1.ClusterState(snapshot.State) {
+	case cmv1.ClusterStateReady:
+		return colorGreen
+	case cmv1.ClusterStateError:
+		return colorRed
+	}
+	return colorYellow
+}
+
+func formatDashboard(snapshots []*clusterSnapshot, highlights map[string]string,
+	recentChanges []*change) string {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "Clusters at %s, refreshing every %s. Press Enter to select a cluster, "+
+		"Ctrl+C to exit.\n\n", time.Now().Format("2006-01-02 15:04:05"), args.interval)
+
+	// Colors are added after aligning the table so that escape sequences don't affect the
+	// width of the columns
+	var table bytes.Buffer
+	writer := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "NAME\tSTATE\tSTATUS\tVERSION\tSCHEDULED UPGRADE\tADD-ONS\n")
+	for _, snapshot := range snapshots {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			snapshot.Name, snapshot.State, snapshot.Description, snapshot.Version,
+			snapshot.Upgrade, snapshot.AddOns)
+	}
+	writer.Flush()
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i > 0 {
+			if color, ok := highlights[snapshots[i-1].ID]; ok {
+				line = color + line + colorReset
+			}
+		}
+		fmt.Fprintf(&out, "%s\n", line)
+	}
+
+	if len(recentChanges) > 0 {
+		fmt.Fprintf(&out, "\nRecent changes:\n")
+		for _, c := range recentChanges {
+			fmt.Fprintf(&out, " - %s\n", c.String())
+		}
+	}
+	return out.String()
+}
+
+// showDetails lets the user select a cluster from the dashboard and describes it.
+func showDetails(reporter *rprtr.Object, ocmClient *ocm.Client, awsCreator *aws.Creator,
+	snapshots []*clusterSnapshot) {
+	if len(snapshots) == 0 {
+		reporter.Warnf("There are no clusters to select")
+		return
+	}
+	options := make([]string, len(snapshots))
+	for i, snapshot := range snapshots {
+		options[i] = fmt.Sprintf("%s (%s)", snapshot.Name, snapshot.ID)
+	}
+	selected, err := interactive.GetOption(interactive.Input{
+		Question: "Cluster",
+		Options:  options,
+		Default:  options[0],
+	})
+	if err != nil {
+		reporter.Warnf("Expected a valid cluster: %v", err)
+		return
+	}
+	for i, option := range options {
+		if option == selected {
+			cluster, err := ocmClient.GetCluster(snapshots[i].ID, awsCreator)
+			if err != nil {
+				reporter.Warnf("Failed to get cluster '%s': %v", snapshots[i].ID, err)
+				return
+			}
+			description, err := describeCluster.Format(ocmClient, cluster)
+			if err != nil {
+				reporter.Warnf("%v", err)
+				return
+			}
+			fmt.Println()
+			fmt.Println(description)
+			return
+		}
+	}
+}
+
+// readLines sends a value to the input channel for each line typed in the terminal. After each
+// line it waits for the resume channel before reading again. The terminal is read one byte at a
+// time so that no input is buffered away from the interactive prompts.
+func readLines(input chan<- bool, resume <-chan bool) {
+	buffer := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buffer)
+		if err != nil {
+			return
+		}
+		if n == 1 && buffer[0] == '\n' {
+			input <- true
+			<-resume
+		}
+	}
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
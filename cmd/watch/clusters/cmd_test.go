@@ -0,0 +1,127 @@
+package clusters_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/cmd/watch/clusters"
+)
+
+func summaries(changes []*clusters.Change) []string {
+	result := []string{}
+	for _, c := range changes {
+		result = append(result, c.Summary())
+	}
+	return result
+}
+
+var _ = Describe("Watch clusters", func() {
+	var ready *clusters.ClusterSnapshot
+
+	BeforeEach(func() {
+		ready = &clusters.ClusterSnapshot{
+			ID:          "abc",
+			Name:        "prod",
+			State:       "ready",
+			Description: "",
+			Version:     "4.8.2",
+			AddOns:      "cluster-logging-operator=ready",
+		}
+	})
+
+	Context("compareSnapshots", func() {
+		It("returns no changes for equal snapshots", func() {
+			current := *ready
+			Expect(clusters.CompareSnapshots(ready, &current)).To(BeEmpty())
+		})
+
+		It("returns a change for each attribute that changed", func() {
+			current := *ready
+			current.Version = "4.8.5"
+			current.Upgrade = "scheduled 4.8.9 on 2021-09-01 10:00 UTC"
+			current.AddOns = ""
+			Expect(summaries(clusters.CompareSnapshots(ready, &current))).To(Equal([]string{
+				"prod version: 4.8.2 -> 4.8.5",
+				"prod scheduled upgrade:  -> scheduled 4.8.9 on 2021-09-01 10:00 UTC",
+				"prod add-ons: cluster-logging-operator=ready -> ",
+			}))
+		})
+
+		It("reports the status description", func() {
+			current := *ready
+			current.State = "error"
+			current.Description = "Install failed"
+			Expect(summaries(clusters.CompareSnapshots(ready, &current))).To(Equal([]string{
+				"prod state: ready -> error",
+				"prod status:  -> Install failed",
+			}))
+		})
+	})
+
+	DescribeTable("getHighlight uses the color of the new state",
+		func(state string, expected string) {
+			Expect(clusters.GetHighlight(&clusters.ClusterSnapshot{State: state})).To(Equal(expected))
+		},
+		Entry("ready", "ready", clusters.ColorGreen),
+		Entry("error", "error", clusters.ColorRed),
+		Entry("installing", "installing", clusters.ColorYellow),
+		Entry("uninstalling", "uninstalling", clusters.ColorYellow),
+	)
+
+	Context("diffSnapshots", func() {
+		It("reports nothing on the first refresh", func() {
+			changes, highlights := clusters.DiffSnapshots(nil, []*clusters.ClusterSnapshot{ready})
+			Expect(changes).To(BeEmpty())
+			Expect(highlights).To(BeEmpty())
+		})
+
+		It("reports nothing when no cluster changed", func() {
+			current := *ready
+			changes, highlights := clusters.DiffSnapshots(
+				map[string]*clusters.ClusterSnapshot{"abc": ready},
+				[]*clusters.ClusterSnapshot{&current},
+			)
+			Expect(changes).To(BeEmpty())
+			Expect(highlights).To(BeEmpty())
+		})
+
+		It("highlights clusters that changed", func() {
+			installing := &clusters.ClusterSnapshot{ID: "def", Name: "dev", State: "installing"}
+			current := *installing
+			current.State = "ready"
+			changes, highlights := clusters.DiffSnapshots(
+				map[string]*clusters.ClusterSnapshot{"abc": ready, "def": installing},
+				[]*clusters.ClusterSnapshot{ready, &current},
+			)
+			Expect(summaries(changes)).To(Equal([]string{"dev state: installing -> ready"}))
+			Expect(highlights).To(Equal(map[string]string{"def": clusters.ColorGreen}))
+		})
+
+		It("reports new clusters as found", func() {
+			installing := &clusters.ClusterSnapshot{ID: "def", Name: "dev", State: "installing"}
+			changes, highlights := clusters.DiffSnapshots(
+				map[string]*clusters.ClusterSnapshot{"abc": ready},
+				[]*clusters.ClusterSnapshot{ready, installing},
+			)
+			Expect(summaries(changes)).To(Equal([]string{"dev state: not found -> installing"}))
+			Expect(highlights).To(Equal(map[string]string{"def": clusters.ColorYellow}))
+		})
+
+		It("reports removed clusters as not found without highlighting them", func() {
+			changes, highlights := clusters.DiffSnapshots(
+				map[string]*clusters.ClusterSnapshot{
+					"abc": ready,
+					"ghi": {ID: "ghi", Name: "test", State: "uninstalling"},
+					"def": {ID: "def", Name: "dev", State: "uninstalling"},
+				},
+				[]*clusters.ClusterSnapshot{ready},
+			)
+			Expect(summaries(changes)).To(Equal([]string{
+				"dev state: uninstalling -> not found",
+				"test state: uninstalling -> not found",
+			}))
+			Expect(highlights).To(BeEmpty())
+		})
+	})
+})
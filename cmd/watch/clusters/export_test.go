@@ -0,0 +1,20 @@
+package clusters
+
+// Unexported types, constants and functions used by the tests of the package
+type ClusterSnapshot = clusterSnapshot
+type Change = change
+
+const (
+	ColorRed    = colorRed
+	ColorGreen  = colorGreen
+	ColorYellow = colorYellow
+)
+
+var CompareSnapshots = compareSnapshots
+var DiffSnapshots = diffSnapshots
+var GetHighlight = getHighlight
+
+// Summary returns the change without the time, so that tests can compare it.
+func (c *change) Summary() string {
+	return c.cluster + " " + c.attribute + ": " + c.from + " -> " + c.to
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
+	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
@@ -39,9 +40,24 @@ var Cmd = &cobra.Command{
 	Run: run,
 }
 
+type userInfo struct {
+	AWSAccountID              string `json:"aws_account_id"`
+	AWSDefaultRegion          string `json:"aws_default_region"`
+	AWSARN                    string `json:"aws_arn"`
+	OCMAPI                    string `json:"ocm_api"`
+	OCMAccountID              string `json:"ocm_account_id"`
+	OCMAccountName            string `json:"ocm_account_name"`
+	OCMAccountUsername        string `json:"ocm_account_username"`
+	OCMAccountEmail           string `json:"ocm_account_email"`
+	OCMOrganizationID         string `json:"ocm_organization_id"`
+	OCMOrganizationName       string `json:"ocm_organization_name"`
+	OCMOrganizationExternalID string `json:"ocm_organization_external_id"`
+}
+
 func init() {
 	flags := Cmd.PersistentFlags()
 	arguments.AddProfileFlag(flags)
+	output.AddFlag(Cmd)
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -123,29 +139,52 @@ func run(_ *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 	}
+	info := &userInfo{
+		AWSAccountID:              awsCreator.AccountID,
+		AWSDefaultRegion:          awsRegion,
+		AWSARN:                    awsCreator.ARN,
+		OCMAPI:                    cfg.URL,
+		OCMAccountID:              account.ID(),
+		OCMAccountName:            fmt.Sprintf("%s %s", account.FirstName(), account.LastName()),
+		OCMAccountUsername:        account.Username(),
+		OCMAccountEmail:           account.Email(),
+		OCMOrganizationID:         account.Organization().ID(),
+		OCMOrganizationName:       account.Organization().Name(),
+		OCMOrganizationExternalID: account.Organization().ExternalID(),
+	}
+
+	if output.HasFlag() {
+		err = output.Print(info)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	fmt.Printf(""+
 		"AWS Account ID:               %s\n"+
 		"AWS Default Region:           %s\n"+
 		"AWS ARN:                      %s\n"+
 		"OCM API:                      %s\n"+
 		"OCM Account ID:               %s\n"+
-		"OCM Account Name:             %s %s\n"+
+		"OCM Account Name:             %s\n"+
 		"OCM Account Username:         %s\n"+
 		"OCM Account Email:            %s\n"+
 		"OCM Organization ID:          %s\n"+
 		"OCM Organization Name:        %s\n"+
 		"OCM Organization External ID: %s\n",
-		awsCreator.AccountID,
-		awsRegion,
-		awsCreator.ARN,
-		cfg.URL,
-		account.ID(),
-		account.FirstName(), account.LastName(),
-		account.Username(),
-		account.Email(),
-		account.Organization().ID(),
-		account.Organization().Name(),
-		account.Organization().ExternalID(),
+		info.AWSAccountID,
+		info.AWSDefaultRegion,
+		info.AWSARN,
+		info.OCMAPI,
+		info.OCMAccountID,
+		info.OCMAccountName,
+		info.OCMAccountUsername,
+		info.OCMAccountEmail,
+		info.OCMOrganizationID,
+		info.OCMOrganizationName,
+		info.OCMOrganizationExternalID,
 	)
 	fmt.Println()
 }
@@ -36,6 +36,11 @@ func AddFlag(flags *pflag.FlagSet) {
 	)
 }
 
+// Yes returns true if the --yes flag was given.
+func Yes() bool {
+	return yes
+}
+
 func Confirm(q string, v ...interface{}) bool {
 	if yes {
 		return yes
@@ -0,0 +1,5 @@
+package kubeconfig
+
+// Unexported functions used by the tests of the package
+var FindNamed = findNamed
+var SetNamed = setNamed
@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to add clusters to kubeconfig files. The files are handled
+// as generic maps so that attributes that rosa doesn't know about are preserved.
+
+package kubeconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Entry contains the cluster and user sections of a kubeconfig context, for example:
+//
+//	Cluster: {"server": "https://api.mycluster.example.com:6443"}
+//	User:    {"token": "sha256~..."}
+type Entry struct {
+	Name    string
+	Cluster map[string]interface{}
+	User    map[string]interface{}
+}
+
+// DefaultPath returns the kubeconfig file used by oc and kubectl: the first file in the
+// KUBECONFIG environment variable or '~/.kube/config'.
+func DefaultPath() (string, error) {
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if path != "" {
+			return path, nil
+		}
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// Parse returns the cluster and user of the current context of the given kubeconfig.
+func Parse(data []byte) (*Entry, error) {
+	config := map[string]interface{}{}
+	err := yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	contextName, _ := config["current-context"].(string)
+	context := findNamed(config, "contexts", contextName)
+	if context == nil {
+		context = findNamed(config, "contexts", "")
+	}
+	if context == nil {
+		return nil, fmt.Errorf("Kubeconfig doesn't contain any context")
+	}
+	contextSection, _ := context["context"].(map[string]interface{})
+	clusterName, _ := contextSection["cluster"].(string)
+	userName, _ := contextSection["user"].(string)
+
+	cluster := findNamed(config, "clusters", clusterName)
+	user := findNamed(config, "users", userName)
+	if cluster == nil || user == nil {
+		return nil, fmt.Errorf("Kubeconfig context '%s' doesn't have a cluster and user", contextName)
+	}
+	entry := &Entry{}
+	entry.Cluster, _ = cluster["cluster"].(map[string]interface{})
+	entry.User, _ = user["user"].(map[string]interface{})
+	return entry, nil
+}
+
+// Merge adds or replaces the cluster, user and context named after the entry in the kubeconfig
+// file and makes it the current context. The file is created if it doesn't exist.
+func Merge(path string, entry *Entry) error {
+	config := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Config",
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		err = yaml.Unmarshal(data, &config)
+		if err != nil {
+			return fmt.Errorf("Failed to parse kubeconfig file '%s': %v", path, err)
+		}
+	}
+
+	setNamed(config, "clusters", entry.Name, "cluster", entry.Cluster)
+	setNamed(config, "users", entry.Name, "user", entry.User)
+	setNamed(config, "contexts", entry.Name, "context", map[string]interface{}{
+		"cluster": entry.Name,
+		"user":    entry.Name,
+	})
+	config["current-context"] = entry.Name
+
+	data, err = yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// findNamed returns the item of the given list that has the given name, or the first item if
+// the name is empty.
+func findNamed(config map[string]interface{}, list string, name string) map[string]interface{} {
+	items, _ := config[list].([]interface{})
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name == "" || object["name"] == name {
+			return object
+		}
+	}
+	return nil
+}
+
+// setNamed replaces the item of the given list that has the given name, or appends it.
+func setNamed(config map[string]interface{}, list string, name string, key string,
+	value map[string]interface{}) {
+	item := map[string]interface{}{
+		"name": name,
+		key:    value,
+	}
+	items, _ := config[list].([]interface{})
+	for i, existing := range items {
+		object, ok := existing.(map[string]interface{})
+		if ok && object["name"] == name {
+			items[i] = item
+			config[list] = items
+			return
+		}
+	}
+	config[list] = append(items, item)
+}
@@ -0,0 +1,262 @@
+package kubeconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/kubeconfig"
+)
+
+// Kubeconfig with a context for another cluster and a context with the same name as the entry
+// that is merged.
+const existing = `apiVersion: v1
+kind: Config
+preferences:
+  colors: true
+current-context: other
+clusters:
+- name: other
+  cluster:
+    server: https://api.other.example.com:6443
+- name: mycluster
+  cluster:
+    server: https://api.old.example.com:6443
+    insecure-skip-tls-verify: true
+users:
+- name: other
+  user:
+    token: sha256~other
+- name: mycluster
+  user:
+    token: sha256~old
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+    namespace: default
+- name: mycluster
+  context:
+    cluster: mycluster
+    user: mycluster
+    namespace: old
+`
+
+var _ = Describe("Kubeconfig", func() {
+	var dir string
+	var path string
+	var entry *kubeconfig.Entry
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "kubeconfig")
+		Expect(err).ToNot(HaveOccurred())
+		path = filepath.Join(dir, ".kube", "config")
+		entry = &kubeconfig.Entry{
+			Name:    "mycluster",
+			Cluster: map[string]interface{}{"server": "https://api.mycluster.example.com:6443"},
+			User:    map[string]interface{}{"token": "sha256~new"},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	read := func() map[string]interface{} {
+		data, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		config := map[string]interface{}{}
+		Expect(yaml.Unmarshal(data, &config)).To(Succeed())
+		return config
+	}
+
+	write := func(data string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(data), 0600)).To(Succeed())
+	}
+
+	Context("Merge", func() {
+		It("creates the file if it doesn't exist", func() {
+			Expect(kubeconfig.Merge(path, entry)).To(Succeed())
+			info, err := os.Stat(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			Expect(read()).To(Equal(map[string]interface{}{
+				"apiVersion":      "v1",
+				"kind":            "Config",
+				"current-context": "mycluster",
+				"clusters": []interface{}{map[string]interface{}{
+					"name":    "mycluster",
+					"cluster": map[string]interface{}{"server": "https://api.mycluster.example.com:6443"},
+				}},
+				"users": []interface{}{map[string]interface{}{
+					"name": "mycluster",
+					"user": map[string]interface{}{"token": "sha256~new"},
+				}},
+				"contexts": []interface{}{map[string]interface{}{
+					"name":    "mycluster",
+					"context": map[string]interface{}{"cluster": "mycluster", "user": "mycluster"},
+				}},
+			}))
+		})
+
+		It("merges into an empty file", func() {
+			write("")
+			Expect(kubeconfig.Merge(path, entry)).To(Succeed())
+			config := read()
+			Expect(config["apiVersion"]).To(Equal("v1"))
+			Expect(config["current-context"]).To(Equal("mycluster"))
+			Expect(config["clusters"]).To(HaveLen(1))
+		})
+
+		It("replaces the entries with the same name and keeps the others", func() {
+			write(existing)
+			Expect(kubeconfig.Merge(path, entry)).To(Succeed())
+			config := read()
+			Expect(config["preferences"]).To(Equal(map[string]interface{}{"colors": true}))
+			Expect(config["clusters"]).To(Equal([]interface{}{
+				map[string]interface{}{
+					"name":    "other",
+					"cluster": map[string]interface{}{"server": "https://api.other.example.com:6443"},
+				},
+				map[string]interface{}{
+					"name":    "mycluster",
+					"cluster": map[string]interface{}{"server": "https://api.mycluster.example.com:6443"},
+				},
+			}))
+			Expect(config["users"]).To(Equal([]interface{}{
+				map[string]interface{}{
+					"name": "other",
+					"user": map[string]interface{}{"token": "sha256~other"},
+				},
+				map[string]interface{}{
+					"name": "mycluster",
+					"user": map[string]interface{}{"token": "sha256~new"},
+				},
+			}))
+			Expect(config["contexts"]).To(Equal([]interface{}{
+				map[string]interface{}{
+					"name": "other",
+					"context": map[string]interface{}{
+						"cluster":   "other",
+						"user":      "other",
+						"namespace": "default",
+					},
+				},
+				map[string]interface{}{
+					"name":    "mycluster",
+					"context": map[string]interface{}{"cluster": "mycluster", "user": "mycluster"},
+				},
+			}))
+		})
+
+		It("makes the entry the current context", func() {
+			write(existing)
+			Expect(kubeconfig.Merge(path, entry)).To(Succeed())
+			Expect(read()["current-context"]).To(Equal("mycluster"))
+		})
+
+		It("fails if the file isn't valid", func() {
+			write("clusters: [")
+			err := kubeconfig.Merge(path, entry)
+			Expect(err).To(MatchError(ContainSubstring("Failed to parse kubeconfig file")))
+		})
+	})
+
+	Context("Parse", func() {
+		It("returns the cluster and user of the current context", func() {
+			parsed, err := kubeconfig.Parse([]byte(existing))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Cluster).To(Equal(map[string]interface{}{
+				"server": "https://api.other.example.com:6443",
+			}))
+			Expect(parsed.User).To(Equal(map[string]interface{}{"token": "sha256~other"}))
+		})
+
+		It("uses the first context if there is no current context", func() {
+			parsed, err := kubeconfig.Parse([]byte(`
+clusters:
+- name: c
+  cluster: {server: "https://api.c.example.com:6443"}
+users:
+- name: u
+  user: {token: "sha256~u"}
+contexts:
+- name: first
+  context: {cluster: c, user: u}
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Cluster["server"]).To(Equal("https://api.c.example.com:6443"))
+			Expect(parsed.User["token"]).To(Equal("sha256~u"))
+		})
+
+		It("fails if there are no contexts", func() {
+			_, err := kubeconfig.Parse([]byte("clusters: []\n"))
+			Expect(err).To(MatchError("Kubeconfig doesn't contain any context"))
+		})
+
+		It("fails if the context doesn't have a cluster and user", func() {
+			_, err := kubeconfig.Parse([]byte(`
+current-context: broken
+contexts:
+- name: broken
+  context: {cluster: missing, user: missing}
+`))
+			Expect(err).To(MatchError("Kubeconfig context 'broken' doesn't have a cluster and user"))
+		})
+
+		It("reads the file written by Merge", func() {
+			Expect(kubeconfig.Merge(path, entry)).To(Succeed())
+			data, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := kubeconfig.Parse(data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Cluster).To(Equal(entry.Cluster))
+			Expect(parsed.User).To(Equal(entry.User))
+		})
+	})
+
+	Context("findNamed and setNamed", func() {
+		var config map[string]interface{}
+
+		BeforeEach(func() {
+			config = map[string]interface{}{
+				"users": []interface{}{
+					"invalid",
+					map[string]interface{}{"name": "a", "user": map[string]interface{}{"token": "1"}},
+					map[string]interface{}{"name": "b", "user": map[string]interface{}{"token": "2"}},
+				},
+			}
+		})
+
+		It("finds items by name and skips invalid ones", func() {
+			Expect(kubeconfig.FindNamed(config, "users", "b")).To(HaveKeyWithValue("name", "b"))
+			Expect(kubeconfig.FindNamed(config, "users", "")).To(HaveKeyWithValue("name", "a"))
+			Expect(kubeconfig.FindNamed(config, "users", "c")).To(BeNil())
+			Expect(kubeconfig.FindNamed(config, "clusters", "a")).To(BeNil())
+		})
+
+		It("replaces the item with the same name in place", func() {
+			kubeconfig.SetNamed(config, "users", "a", "user", map[string]interface{}{"token": "3"})
+			Expect(config["users"]).To(HaveLen(3))
+			Expect(kubeconfig.FindNamed(config, "users", "")).To(Equal(map[string]interface{}{
+				"name": "a",
+				"user": map[string]interface{}{"token": "3"},
+			}))
+		})
+
+		It("appends new items, creating the list if needed", func() {
+			kubeconfig.SetNamed(config, "users", "c", "user", map[string]interface{}{"token": "3"})
+			Expect(config["users"]).To(HaveLen(4))
+			kubeconfig.SetNamed(config, "clusters", "c", "cluster", map[string]interface{}{})
+			Expect(config["clusters"]).To(HaveLen(1))
+		})
+	})
+})
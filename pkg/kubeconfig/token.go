@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to request an OAuth access token from the cluster, the
+// same way that 'oc login' does it for users of identity providers that accept passwords.
+
+package kubeconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client used by 'oc' to request tokens using basic authentication
+const challengingClientID = "openshift-challenging-client"
+
+var httpClient = &http.Client{
+	Timeout: 30 * time.Second,
+	// The token is returned in the fragment of the redirect, so it must not be followed
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// RequestToken logs in to the OAuth server of the cluster with the given API URL and returns
+// an access token for the user.
+func RequestToken(apiURL string, username string, password string) (string, error) {
+	authorizationEndpoint, err := getAuthorizationEndpoint(apiURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("client_id", challengingClientID)
+	query.Set("response_type", "token")
+	request, err := http.NewRequest(http.MethodGet, authorizationEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	request.SetBasicAuth(username, password)
+	// Required by the OAuth server for requests that don't come from a browser
+	request.Header.Set("X-CSRF-Token", "1")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusFound:
+	case http.StatusUnauthorized:
+		return "", fmt.Errorf("Login failed: invalid username or password for user '%s'", username)
+	default:
+		return "", fmt.Errorf("Login failed: unexpected response from OAuth server: %s", response.Status)
+	}
+
+	location, err := response.Location()
+	if err != nil {
+		return "", err
+	}
+	fragment, err := url.ParseQuery(location.Fragment)
+	if err != nil {
+		return "", err
+	}
+	if fragment.Get("error") != "" {
+		return "", fmt.Errorf("Login failed: %s", fragment.Get("error_description"))
+	}
+	token := fragment.Get("access_token")
+	if token == "" {
+		return "", fmt.Errorf("Login failed: OAuth server didn't return an access token")
+	}
+	return token, nil
+}
+
+// getAuthorizationEndpoint discovers the OAuth authorization endpoint of the cluster.
+func getAuthorizationEndpoint(apiURL string) (string, error) {
+	discoveryURL := strings.TrimSuffix(apiURL, "/") + "/.well-known/oauth-authorization-server"
+	response, err := httpClient.Get(discoveryURL)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to discover OAuth server of '%s': %s", apiURL, response.Status)
+	}
+
+	var metadata struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&metadata)
+	if err != nil {
+		return "", err
+	}
+	if metadata.AuthorizationEndpoint == "" {
+		return "", fmt.Errorf("OAuth server of '%s' doesn't have an authorization endpoint", apiURL)
+	}
+	return metadata.AuthorizationEndpoint, nil
+}
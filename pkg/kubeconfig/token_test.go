@@ -0,0 +1,95 @@
+package kubeconfig_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/kubeconfig"
+)
+
+var _ = Describe("RequestToken", func() {
+	var server *httptest.Server
+	var authorize http.HandlerFunc
+
+	BeforeEach(func() {
+		authorize = nil
+		mux := http.NewServeMux()
+		mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter,
+			r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"authorization_endpoint": server.URL + "/oauth/authorize",
+			})
+		})
+		mux.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, r *http.Request) {
+			authorize(w, r)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	redirect := func(fragment string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", server.URL+"/oauth/token/implicit#"+fragment)
+			w.WriteHeader(http.StatusFound)
+		}
+	}
+
+	It("returns the token from the redirect", func() {
+		var request *http.Request
+		authorize = func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			redirect("access_token=sha256~abc&token_type=Bearer")(w, r)
+		}
+		token, err := kubeconfig.RequestToken(server.URL+"/", "admin", "secret")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("sha256~abc"))
+
+		username, password, ok := request.BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(username).To(Equal("admin"))
+		Expect(password).To(Equal("secret"))
+		Expect(request.Header.Get("X-CSRF-Token")).ToNot(BeEmpty())
+		Expect(request.URL.Query().Get("client_id")).To(Equal("openshift-challenging-client"))
+		Expect(request.URL.Query().Get("response_type")).To(Equal("token"))
+	})
+
+	It("fails with invalid credentials", func() {
+		authorize = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+		_, err := kubeconfig.RequestToken(server.URL, "admin", "wrong")
+		Expect(err).To(MatchError("Login failed: invalid username or password for user 'admin'"))
+	})
+
+	It("fails with unexpected responses", func() {
+		authorize = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, err := kubeconfig.RequestToken(server.URL, "admin", "secret")
+		Expect(err).To(MatchError(ContainSubstring("unexpected response from OAuth server: 500")))
+	})
+
+	It("fails with the error returned in the redirect", func() {
+		authorize = redirect("error=access_denied&error_description=User+is+not+allowed")
+		_, err := kubeconfig.RequestToken(server.URL, "admin", "secret")
+		Expect(err).To(MatchError("Login failed: User is not allowed"))
+	})
+
+	It("fails if the redirect doesn't contain a token", func() {
+		authorize = redirect("token_type=Bearer")
+		_, err := kubeconfig.RequestToken(server.URL, "admin", "secret")
+		Expect(err).To(MatchError("Login failed: OAuth server didn't return an access token"))
+	})
+
+	It("fails if the OAuth server can't be discovered", func() {
+		_, err := kubeconfig.RequestToken(server.URL+"/missing", "admin", "secret")
+		Expect(err).To(MatchError(ContainSubstring("Failed to discover OAuth server")))
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the command line flags shared by the commands that configure an LDAP
+// identity provider.
+
+package ldap
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Flags contains the values of the command line flags of an LDAP identity provider. Lists of
+// attributes are kept as given, separated by commas.
+type Flags struct {
+	URL                string
+	Insecure           bool
+	BindDN             string
+	BindPassword       string
+	IDs                string
+	PreferredUsernames string
+	Names              string
+	Emails             string
+}
+
+// AddFlags adds the flags of an LDAP identity provider to the given set, storing their values
+// in the given flags.
+func AddFlags(flags *pflag.FlagSet, values *Flags) {
+	flags.StringVar(
+		&values.URL,
+		"url",
+		"",
+		"LDAP: An RFC 2255 URL which specifies the LDAP search parameters to use.",
+	)
+	flags.BoolVar(
+		&values.Insecure,
+		"insecure",
+		false,
+		"LDAP: Do not make TLS connections to the server.",
+	)
+	flags.StringVar(
+		&values.BindDN,
+		"bind-dn",
+		"",
+		"LDAP: DN to bind with during the search phase.",
+	)
+	flags.StringVar(
+		&values.BindPassword,
+		"bind-password",
+		"",
+		"LDAP: Password to bind with during the search phase.",
+	)
+	flags.StringVar(
+		&values.IDs,
+		"id-attributes",
+		"dn",
+		"LDAP: The list of attributes whose values should be used as the user ID.",
+	)
+	flags.StringVar(
+		&values.PreferredUsernames,
+		"username-attributes",
+		"uid",
+		"LDAP: The list of attributes whose values should be used as the preferred username.",
+	)
+	flags.StringVar(
+		&values.Names,
+		"name-attributes",
+		"cn",
+		"LDAP: The list of attributes whose values should be used as the display name.",
+	)
+	flags.StringVar(
+		&values.Emails,
+		"email-attributes",
+		"",
+		"LDAP: The list of attributes whose values should be used as the email address.\n",
+	)
+}
+
+// SplitAttributes returns the attributes of a comma separated list, or an empty list when the
+// given text is empty.
+func SplitAttributes(attributes string) []string {
+	if attributes == "" {
+		return []string{}
+	}
+	return strings.Split(attributes, ",")
+}
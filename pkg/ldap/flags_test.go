@@ -0,0 +1,35 @@
+package ldap_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/ldap"
+)
+
+var _ = Describe("Flags", func() {
+	It("uses the defaults of the OAuth server", func() {
+		var values ldap.Flags
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		ldap.AddFlags(flags, &values)
+		Expect(flags.Parse([]string{"--url", "ldap://ldap.example.com", "--email-attributes", "mail"})).To(Succeed())
+		Expect(values).To(Equal(ldap.Flags{
+			URL:                "ldap://ldap.example.com",
+			IDs:                "dn",
+			PreferredUsernames: "uid",
+			Names:              "cn",
+			Emails:             "mail",
+		}))
+	})
+
+	DescribeTable("SplitAttributes",
+		func(attributes string, expected []string) {
+			Expect(ldap.SplitAttributes(attributes)).To(Equal(expected))
+		},
+		Entry("empty", "", []string{}),
+		Entry("single", "uid", []string{"uid"}),
+		Entry("multiple", "mail,userPrincipalName", []string{"mail", "userPrincipalName"}),
+	)
+})
@@ -0,0 +1,263 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check an LDAP identity provider configuration
+// against the LDAP server, the same way that the OAuth server of the cluster uses it.
+
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const timeout = 10 * time.Second
+
+// URL is an RFC 2255 LDAP URL of the form ldap://host:port/basedn?attribute?scope?filter.
+type URL struct {
+	Scheme    string
+	Host      string
+	BaseDN    string
+	Attribute string
+	Scope     int
+	Filter    string
+}
+
+// Config contains the settings of an LDAP identity provider.
+type Config struct {
+	URL                string
+	Insecure           bool
+	CA                 string
+	BindDN             string
+	BindPassword       string
+	IDs                []string
+	PreferredUsernames []string
+	Names              []string
+	Emails             []string
+}
+
+// Attribute describes how one of the identity attributes resolved for a user.
+type Attribute struct {
+	Name       string
+	Configured []string
+	Resolved   string
+	Value      string
+}
+
+// Result contains the entry found for a user and how each identity attribute resolved.
+type Result struct {
+	DN         string
+	Attributes []Attribute
+}
+
+// ParseURL parses an RFC 2255 LDAP URL, filling in the defaults used by the OAuth server.
+func ParseURL(rawURL string) (*URL, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("Expected a valid LDAP URL: %v", err)
+	}
+
+	result := &URL{
+		Scheme:    parsedURL.Scheme,
+		Host:      parsedURL.Host,
+		BaseDN:    strings.TrimPrefix(parsedURL.Path, "/"),
+		Attribute: "uid",
+		Scope:     goldap.ScopeWholeSubtree,
+		Filter:    "(objectClass=*)",
+	}
+	switch result.Scheme {
+	case "ldap":
+		if parsedURL.Port() == "" {
+			result.Host = net.JoinHostPort(parsedURL.Hostname(), "389")
+		}
+	case "ldaps":
+		if parsedURL.Port() == "" {
+			result.Host = net.JoinHostPort(parsedURL.Hostname(), "636")
+		}
+	default:
+		return nil, errors.New("Expected LDAP URL to have an ldap:// or ldaps:// scheme")
+	}
+	if parsedURL.Hostname() == "" {
+		return nil, errors.New("Expected LDAP URL to have a host")
+	}
+
+	parts := strings.SplitN(parsedURL.RawQuery, "?", 4)
+	for i, part := range parts {
+		parts[i], err = url.QueryUnescape(part)
+		if err != nil {
+			return nil, fmt.Errorf("Expected a valid LDAP URL: %v", err)
+		}
+	}
+	if len(parts) > 0 && parts[0] != "" {
+		if strings.Contains(parts[0], ",") {
+			return nil, errors.New("LDAP URL must contain a single attribute")
+		}
+		result.Attribute = parts[0]
+	}
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "", "sub":
+			result.Scope = goldap.ScopeWholeSubtree
+		case "one":
+			result.Scope = goldap.ScopeSingleLevel
+		case "base":
+			result.Scope = goldap.ScopeBaseObject
+		default:
+			return nil, fmt.Errorf("Expected LDAP URL scope to be one of [base one sub], got '%s'", parts[1])
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		_, err = goldap.CompileFilter(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("Expected a valid LDAP URL filter: %v", err)
+		}
+		result.Filter = parts[2]
+	}
+	if len(parts) > 3 && parts[3] != "" {
+		return nil, errors.New("LDAP URL extensions are not supported")
+	}
+
+	return result, nil
+}
+
+// Verify connects to the LDAP server of the configuration, binds with the configured DN, looks up
+// the given user and reports which of the configured identity attributes resolve for it.
+func Verify(config Config, username string) (*Result, error) {
+	ldapURL, err := ParseURL(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if ldapURL.Scheme == "ldaps" && config.Insecure {
+		return nil, errors.New("Cannot use insecure connection on ldaps URLs")
+	}
+	if config.Insecure && config.CA != "" {
+		return nil, errors.New("Cannot use certificate bundle with an insecure connection")
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: strings.Split(ldapURL.Host, ":")[0],
+	}
+	if config.CA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CA)) {
+			return nil, errors.New("Expected a valid certificate bundle: no PEM certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	conn, err := goldap.DialURL(
+		fmt.Sprintf("%s://%s", ldapURL.Scheme, ldapURL.Host),
+		goldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		goldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to '%s': %v", ldapURL.Host, err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(timeout)
+
+	// Plain connections are upgraded with StartTLS unless they are explicitly insecure, like
+	// the OAuth server does:
+	if ldapURL.Scheme == "ldap" && !config.Insecure {
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to start TLS with '%s': %v", ldapURL.Host, err)
+		}
+	}
+
+	if config.BindDN != "" {
+		err = conn.Bind(config.BindDN, config.BindPassword)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to bind as '%s': %v", config.BindDN, err)
+		}
+	}
+
+	attributes := []Attribute{
+		{Name: "id", Configured: config.IDs},
+		{Name: "username", Configured: config.PreferredUsernames},
+		{Name: "name", Configured: config.Names},
+		{Name: "email", Configured: config.Emails},
+	}
+	requested := []string{ldapURL.Attribute}
+	for _, attribute := range attributes {
+		for _, name := range attribute.Configured {
+			// The DN is part of every entry, it isn't an attribute that can be requested
+			if name != "dn" {
+				requested = append(requested, name)
+			}
+		}
+	}
+
+	filter := fmt.Sprintf("(&%s(%s=%s))", ldapURL.Filter, ldapURL.Attribute, goldap.EscapeFilter(username))
+	response, err := conn.Search(goldap.NewSearchRequest(
+		ldapURL.BaseDN,
+		ldapURL.Scope,
+		goldap.NeverDerefAliases,
+		2,
+		int(timeout.Seconds()),
+		false,
+		filter,
+		requested,
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search for user '%s' with filter '%s': %v", username, filter, err)
+	}
+	if len(response.Entries) == 0 {
+		return nil, fmt.Errorf("User '%s' not found with filter '%s' under '%s'", username, filter, ldapURL.BaseDN)
+	}
+	if len(response.Entries) > 1 {
+		return nil, fmt.Errorf("Multiple entries found for user '%s' with filter '%s'", username, filter)
+	}
+
+	entry := response.Entries[0]
+	for i, attribute := range attributes {
+		for _, name := range attribute.Configured {
+			value := entry.GetAttributeValue(name)
+			if name == "dn" {
+				value = entry.DN
+			}
+			if value != "" {
+				attributes[i].Resolved = name
+				attributes[i].Value = value
+				break
+			}
+		}
+	}
+
+	return &Result{
+		DN:         entry.DN,
+		Attributes: attributes,
+	}, nil
+}
+
+// Validate checks that the result contains an ID for the user, which is required to log in.
+func (r *Result) Validate() error {
+	for _, attribute := range r.Attributes {
+		if attribute.Name == "id" && attribute.Resolved == "" {
+			return fmt.Errorf("None of the ID attributes %s resolve for '%s'", attribute.Configured, r.DN)
+		}
+	}
+	return nil
+}
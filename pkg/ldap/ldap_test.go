@@ -0,0 +1,174 @@
+package ldap_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ldap"
+)
+
+var _ = Describe("LDAP", func() {
+	Context("ParseURL", func() {
+		It("fills in the defaults", func() {
+			url, err := ldap.ParseURL("ldap://ldap.example.com/ou=users,dc=example,dc=com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url.Host).To(Equal("ldap.example.com:389"))
+			Expect(url.BaseDN).To(Equal("ou=users,dc=example,dc=com"))
+			Expect(url.Attribute).To(Equal("uid"))
+			Expect(url.Scope).To(Equal(2))
+			Expect(url.Filter).To(Equal("(objectClass=*)"))
+		})
+
+		It("parses all the search parameters", func() {
+			url, err := ldap.ParseURL("ldaps://ldap.example.com:1636/dc=example,dc=com?mail?one?(objectClass=person)")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url.Host).To(Equal("ldap.example.com:1636"))
+			Expect(url.Attribute).To(Equal("mail"))
+			Expect(url.Scope).To(Equal(1))
+			Expect(url.Filter).To(Equal("(objectClass=person)"))
+		})
+
+		It("defaults the port of ldaps URLs", func() {
+			url, err := ldap.ParseURL("ldaps://ldap.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url.Host).To(Equal("ldap.example.com:636"))
+		})
+
+		DescribeTable("rejects invalid URLs",
+			func(url string, message string) {
+				_, err := ldap.ParseURL(url)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("wrong scheme", "https://ldap.example.com", "scheme"),
+			Entry("no host", "ldap:///dc=example", "host"),
+			Entry("multiple attributes", "ldap://ldap.example.com/dc=example?uid,mail", "single attribute"),
+			Entry("unknown scope", "ldap://ldap.example.com/dc=example?uid?all", "scope"),
+			Entry("invalid filter", "ldap://ldap.example.com/dc=example?uid?sub?objectClass=*", "filter"),
+			Entry("extensions", "ldap://ldap.example.com/dc=example?uid?sub??!bindname=x", "extensions"),
+		)
+	})
+
+	Context("Verify", func() {
+		var (
+			s      *server
+			config ldap.Config
+		)
+
+		start := func(secure bool) {
+			s = newServer(secure)
+			s.bindDN = "cn=reader,dc=example,dc=com"
+			s.bindPassword = "secret"
+			s.entries["uid=alice,ou=users,dc=example,dc=com"] = map[string][]string{
+				"uid":  {"alice"},
+				"cn":   {"Alice Liddell"},
+				"mail": {"alice@example.com"},
+			}
+			scheme := "ldap"
+			if secure {
+				scheme = "ldaps"
+			}
+			config = ldap.Config{
+				URL:                scheme + "://" + s.Addr() + "/ou=users,dc=example,dc=com?uid",
+				CA:                 s.CA(),
+				BindDN:             s.bindDN,
+				BindPassword:       s.bindPassword,
+				IDs:                []string{"dn"},
+				PreferredUsernames: []string{"uid"},
+				Names:              []string{"displayName", "cn"},
+				Emails:             []string{"email"},
+			}
+		}
+
+		AfterEach(func() {
+			s.Close()
+		})
+
+		Context("with StartTLS", func() {
+			BeforeEach(func() {
+				start(false)
+			})
+
+			It("reports which attributes resolve", func() {
+				result, err := ldap.Verify(config, "alice")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.DN).To(Equal("uid=alice,ou=users,dc=example,dc=com"))
+				Expect(result.Attributes).To(Equal([]ldap.Attribute{
+					{
+						Name:       "id",
+						Configured: []string{"dn"},
+						Resolved:   "dn",
+						Value:      "uid=alice,ou=users,dc=example,dc=com",
+					},
+					{Name: "username", Configured: []string{"uid"}, Resolved: "uid", Value: "alice"},
+					{Name: "name", Configured: []string{"displayName", "cn"}, Resolved: "cn", Value: "Alice Liddell"},
+					{Name: "email", Configured: []string{"email"}},
+				}))
+				Expect(result.Validate()).To(Succeed())
+			})
+
+			It("fails when the CA doesn't sign the server certificate", func() {
+				config.CA = ""
+				_, err := ldap.Verify(config, "alice")
+				Expect(err).To(MatchError(ContainSubstring("Failed to start TLS")))
+			})
+
+			It("fails when the bind password is wrong", func() {
+				config.BindPassword = "wrong"
+				_, err := ldap.Verify(config, "alice")
+				Expect(err).To(MatchError(ContainSubstring("Failed to bind")))
+			})
+
+			It("fails when the user doesn't exist", func() {
+				_, err := ldap.Verify(config, "bob")
+				Expect(err).To(MatchError(ContainSubstring("User 'bob' not found")))
+			})
+
+			It("fails validation when no ID attribute resolves", func() {
+				config.IDs = []string{"employeeNumber"}
+				result, err := ldap.Verify(config, "alice")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Validate()).To(MatchError(ContainSubstring("None of the ID attributes")))
+			})
+		})
+
+		Context("without TLS", func() {
+			BeforeEach(func() {
+				start(false)
+			})
+
+			It("connects when insecure", func() {
+				config.Insecure = true
+				config.CA = ""
+				result, err := ldap.Verify(config, "alice")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.DN).To(Equal("uid=alice,ou=users,dc=example,dc=com"))
+			})
+
+			It("rejects a CA on insecure connections", func() {
+				config.Insecure = true
+				_, err := ldap.Verify(config, "alice")
+				Expect(err).To(MatchError(ContainSubstring("insecure connection")))
+			})
+		})
+
+		Context("with ldaps", func() {
+			BeforeEach(func() {
+				start(true)
+			})
+
+			It("connects with the CA", func() {
+				result, err := ldap.Verify(config, "alice")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Validate()).To(Succeed())
+			})
+
+			It("rejects insecure connections", func() {
+				config.Insecure = true
+				config.CA = ""
+				_, err := ldap.Verify(config, "alice")
+				Expect(err).To(MatchError(ContainSubstring("Cannot use insecure connection on ldaps URLs")))
+			})
+		})
+	})
+})
@@ -0,0 +1,193 @@
+package ldap_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	. "github.com/onsi/gomega"
+)
+
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// server is a minimal stand-in for an LDAP server that supports simple binds, StartTLS and
+// searches for entries by 'uid'.
+type server struct {
+	listener     net.Listener
+	tlsConfig    *tls.Config
+	ca           string
+	bindDN       string
+	bindPassword string
+	entries      map[string]map[string][]string
+}
+
+func newServer(secure bool) *server {
+	cert, ca := generateCertificate()
+	s := &server{
+		tlsConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		},
+		ca:      ca,
+		entries: map[string]map[string][]string{},
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	if secure {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+	s.listener = listener
+	go s.serve()
+	return s
+}
+
+// CA returns the PEM encoded certificate that signs the certificate of the server.
+func (s *server) CA() string {
+	return s.ca
+}
+
+func (s *server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *server) Close() {
+	s.listener.Close()
+}
+
+func (s *server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer func() {
+		// The connection is replaced when it is upgraded with StartTLS
+		conn.Close()
+	}()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			name := op.Children[1].Data.String()
+			password := op.Children[2].Data.String()
+			code := goldap.LDAPResultSuccess
+			if name != "" && (name != s.bindDN || password != s.bindPassword) {
+				code = goldap.LDAPResultInvalidCredentials
+			}
+			s.reply(conn, id, result(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationExtendedRequest:
+			if op.Children[0].Data.String() != startTLSOID {
+				s.reply(conn, id, result(goldap.ApplicationExtendedResponse, goldap.LDAPResultProtocolError))
+				continue
+			}
+			s.reply(conn, id, result(goldap.ApplicationExtendedResponse, goldap.LDAPResultSuccess))
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			err = tlsConn.Handshake()
+			if err != nil {
+				return
+			}
+			conn = tlsConn
+		case goldap.ApplicationSearchRequest:
+			filter, err := goldap.DecompileFilter(op.Children[6])
+			if err != nil {
+				s.reply(conn, id, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError))
+				continue
+			}
+			requested := map[string]bool{}
+			for _, attribute := range op.Children[7].Children {
+				requested[attribute.Data.String()] = true
+			}
+			for dn, attributes := range s.entries {
+				if !strings.Contains(filter, fmt.Sprintf("(uid=%s)", attributes["uid"][0])) {
+					continue
+				}
+				entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed,
+					goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+				entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "DN"))
+				list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+				for name, values := range attributes {
+					if !requested[name] {
+						continue
+					}
+					attribute := ber.NewSequence("Attribute")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString,
+						name, "Type"))
+					set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+					for _, value := range values {
+						set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString,
+							value, "Value"))
+					}
+					attribute.AppendChild(set)
+					list.AppendChild(attribute)
+				}
+				entry.AppendChild(list)
+				s.reply(conn, id, entry)
+			}
+			s.reply(conn, id, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+		default:
+			return
+		}
+	}
+}
+
+func (s *server) reply(conn net.Conn, id int64, op *ber.Packet) {
+	packet := ber.NewSequence("LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	packet.AppendChild(op)
+	conn.Write(packet.Bytes())
+}
+
+func result(tag ber.Tag, code int) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code),
+		"Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Message"))
+	return packet
+}
+
+// generateCertificate returns a self-signed certificate for 127.0.0.1 and its PEM encoding.
+func generateCertificate() (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: der,
+	}))
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package ocm
 
 import (
+	"bytes"
+	"encoding/json"
+
 	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
@@ -34,10 +37,29 @@ type AddOnResource struct {
 	Available bool
 }
 
+// MarshalJSON writes the add-on using the JSON representation of the OCM API, together with
+// its availability.
+func (r *AddOnResource) MarshalJSON() ([]byte, error) {
+	var addOn bytes.Buffer
+	err := cmv1.MarshalAddOn(r.AddOn, &addOn)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		AddOn     json.RawMessage `json:"addon"`
+		AZType    string          `json:"az_type"`
+		Available bool            `json:"available"`
+	}{
+		AddOn:     addOn.Bytes(),
+		AZType:    r.AZType,
+		Available: r.Available,
+	})
+}
+
 type ClusterAddOn struct {
-	ID    string
-	Name  string
-	State string
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
 }
 
 func (c *Client) InstallAddOn(clusterKey string, creator *aws.Creator, addOnID string,
@@ -247,6 +269,21 @@ func (c *Client) GetAddOn(id string) (*cmv1.AddOn, error) {
 	return response.Body(), nil
 }
 
+// Get the add-ons installed on a cluster
+func (c *Client) GetAddOnInstallations(clusterID string) ([]*cmv1.AddOnInstallation, error) {
+	response, err := c.ocm.ClustersMgmt().V1().Clusters().
+		Cluster(clusterID).
+		Addons().
+		List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Items().Slice(), nil
+}
+
 // Get all add-ons available for a cluster
 func (c *Client) GetClusterAddOns(cluster *cmv1.Cluster) ([]*ClusterAddOn, error) {
 	addOnResources, err := c.GetAvailableAddOns()
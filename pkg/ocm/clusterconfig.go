@@ -0,0 +1,412 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to export the day-2 configuration of a
+// cluster to a document that can be used to recreate it on another cluster.
+
+package ocm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Identity provider and user created by 'rosa create admin', which are specific to each cluster
+const (
+	AdminIdentityProvider = "Cluster-Admin"
+	AdminUsername         = "cluster-admin"
+)
+
+// UserGroups are the groups that users can be granted
+var UserGroups = []string{"dedicated-admins", "cluster-admins"}
+
+// Attributes of the identity provider types that contain secrets, which the API doesn't return
+var identityProviderSecrets = map[string]string{
+	"github":   "client_secret",
+	"gitlab":   "client_secret",
+	"google":   "client_secret",
+	"htpasswd": "password",
+	"ldap":     "bind_password",
+	"open_id":  "client_secret",
+}
+
+// ClusterConfig is the day-2 configuration of a cluster.
+type ClusterConfig struct {
+	Cluster           string                   `json:"cluster,omitempty"`
+	MachinePools      []*MachinePoolConfig     `json:"machine_pools,omitempty"`
+	IdentityProviders []map[string]interface{} `json:"identity_providers,omitempty"`
+	Ingresses         []*IngressConfig         `json:"ingresses,omitempty"`
+	AddOns            []*AddOnConfig           `json:"addons,omitempty"`
+	Users             map[string][]string      `json:"users,omitempty"`
+	Upgrade           *UpgradeConfig           `json:"upgrade,omitempty"`
+}
+
+type MachinePoolConfig struct {
+	ID                string             `json:"id"`
+	InstanceType      string             `json:"instance_type"`
+	Replicas          int                `json:"replicas,omitempty"`
+	Autoscaling       *AutoscalingConfig `json:"autoscaling,omitempty"`
+	AvailabilityZones []string           `json:"availability_zones,omitempty"`
+	Labels            map[string]string  `json:"labels,omitempty"`
+	Taints            []*TaintConfig     `json:"taints,omitempty"`
+}
+
+type AutoscalingConfig struct {
+	MinReplicas int `json:"min_replicas"`
+	MaxReplicas int `json:"max_replicas"`
+}
+
+type TaintConfig struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+type IngressConfig struct {
+	Default        bool              `json:"default,omitempty"`
+	Private        bool              `json:"private,omitempty"`
+	RouteSelectors map[string]string `json:"route_selectors,omitempty"`
+}
+
+type AddOnConfig struct {
+	ID         string            `json:"id"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+type UpgradeConfig struct {
+	Schedule             string `json:"schedule,omitempty"`
+	NodeDrainGracePeriod string `json:"node_drain_grace_period,omitempty"`
+}
+
+// LoadClusterConfig reads a cluster configuration from a YAML or JSON file.
+func LoadClusterConfig(path string) (*ClusterConfig, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read file '%s': %v", path, err)
+	}
+	config := &ClusterConfig{}
+	err = yaml.Unmarshal(body, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse file '%s': %v", path, err)
+	}
+	return config, nil
+}
+
+// ExportClusterConfig reads the day-2 configuration of the cluster. Secrets of identity providers
+// are replaced by placeholders that reference environment variables. The admin identity provider
+// and user are left out, as well as one-off upgrades, since they only make sense on this cluster.
+func (c *Client) ExportClusterConfig(cluster *cmv1.Cluster) (*ClusterConfig, error) {
+	config := &ClusterConfig{
+		Cluster: cluster.Name(),
+		Users:   map[string][]string{},
+	}
+
+	machinePools, err := c.GetMachinePools(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get machine pools: %v", err)
+	}
+	for _, machinePool := range machinePools {
+		config.MachinePools = append(config.MachinePools, NewMachinePoolConfig(machinePool))
+	}
+
+	idps, err := c.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get identity providers: %v", err)
+	}
+	for _, idp := range idps {
+		if idp.Name() == AdminIdentityProvider {
+			continue
+		}
+		idpConfig, err := NewIdentityProviderConfig(idp)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to export identity provider '%s': %v", idp.Name(), err)
+		}
+		config.IdentityProviders = append(config.IdentityProviders, idpConfig)
+	}
+
+	ingresses, err := c.GetIngresses(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get ingresses: %v", err)
+	}
+	for _, ingress := range ingresses {
+		config.Ingresses = append(config.Ingresses, NewIngressConfig(ingress))
+	}
+
+	addOns, err := c.GetAddOnInstallations(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get add-ons: %v", err)
+	}
+	for _, addOn := range addOns {
+		config.AddOns = append(config.AddOns, NewAddOnConfig(addOn))
+	}
+
+	for _, group := range UserGroups {
+		users, err := c.GetUsers(cluster.ID(), group)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get users of group '%s': %v", group, err)
+		}
+		for _, user := range users {
+			if user.ID() == AdminUsername {
+				continue
+			}
+			config.Users[group] = append(config.Users[group], user.ID())
+		}
+		sort.Strings(config.Users[group])
+	}
+
+	upgradePolicies, err := c.GetUpgradePolicies(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get upgrade policies: %v", err)
+	}
+	upgrade := &UpgradeConfig{
+		NodeDrainGracePeriod: GetNodeDrainGracePeriod(cluster),
+	}
+	for _, upgradePolicy := range upgradePolicies {
+		if upgradePolicy.ScheduleType() == "automatic" {
+			upgrade.Schedule = upgradePolicy.Schedule()
+		}
+	}
+	if upgrade.Schedule != "" || upgrade.NodeDrainGracePeriod != "" {
+		config.Upgrade = upgrade
+	}
+
+	return config, nil
+}
+
+func NewMachinePoolConfig(machinePool *cmv1.MachinePool) *MachinePoolConfig {
+	config := &MachinePoolConfig{
+		ID:                machinePool.ID(),
+		InstanceType:      machinePool.InstanceType(),
+		AvailabilityZones: machinePool.AvailabilityZones(),
+		Labels:            machinePool.Labels(),
+	}
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		config.Autoscaling = &AutoscalingConfig{
+			MinReplicas: autoscaling.MinReplicas(),
+			MaxReplicas: autoscaling.MaxReplicas(),
+		}
+	} else {
+		config.Replicas = machinePool.Replicas()
+	}
+	for _, taint := range machinePool.Taints() {
+		config.Taints = append(config.Taints, &TaintConfig{
+			Key:    taint.Key(),
+			Value:  taint.Value(),
+			Effect: taint.Effect(),
+		})
+	}
+	return config
+}
+
+// Build returns the machine pool described by the configuration.
+func (m *MachinePoolConfig) Build() (*cmv1.MachinePool, error) {
+	builder := m.builder().
+		InstanceType(m.InstanceType)
+	if len(m.AvailabilityZones) > 0 {
+		builder = builder.AvailabilityZones(m.AvailabilityZones...)
+	}
+	return builder.Build()
+}
+
+// buildUpdate returns the machine pool described by the configuration without the attributes
+// that can't be changed once it is created.
+func (m *MachinePoolConfig) buildUpdate() (*cmv1.MachinePool, error) {
+	return m.builder().Build()
+}
+
+func (m *MachinePoolConfig) builder() *cmv1.MachinePoolBuilder {
+	builder := cmv1.NewMachinePool().
+		ID(m.ID).
+		Labels(m.Labels)
+	if m.Autoscaling != nil {
+		builder = builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().
+			MinReplicas(m.Autoscaling.MinReplicas).
+			MaxReplicas(m.Autoscaling.MaxReplicas))
+	} else {
+		builder = builder.Replicas(m.Replicas)
+	}
+	taints := []*cmv1.TaintBuilder{}
+	for _, taint := range m.Taints {
+		taints = append(taints, cmv1.NewTaint().
+			Key(taint.Key).
+			Value(taint.Value).
+			Effect(taint.Effect))
+	}
+	return builder.Taints(taints...)
+}
+
+// NewIdentityProviderConfig returns the JSON representation of the identity provider without the
+// attributes that are specific to the cluster, and with the secrets replaced by placeholders.
+func NewIdentityProviderConfig(idp *cmv1.IdentityProvider) (map[string]interface{}, error) {
+	var b bytes.Buffer
+	err := cmv1.MarshalIdentityProvider(idp, &b)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &config)
+	if err != nil {
+		return nil, err
+	}
+	delete(config, "kind")
+	delete(config, "id")
+	delete(config, "href")
+	for key, secret := range identityProviderSecrets {
+		attributes, ok := config[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// LDAP servers can be searched anonymously
+		if key == "ldap" && attributes["bind_dn"] == nil {
+			continue
+		}
+		attributes[secret] = secretPlaceholder(idp.Name(), secret)
+	}
+	return config, nil
+}
+
+// BuildIdentityProvider returns the identity provider described by the configuration, replacing
+// the secret placeholders with the values of the environment variables that they reference.
+func BuildIdentityProvider(config map[string]interface{}) (*cmv1.IdentityProvider, error) {
+	return buildIdentityProvider(config, true)
+}
+
+// buildIdentityProvider returns the identity provider described by the configuration. Unless the
+// secrets are required, the ones whose environment variables aren't set are left out.
+func buildIdentityProvider(config map[string]interface{}, requireSecrets bool) (*cmv1.IdentityProvider, error) {
+	missing := []string{}
+	expand := func(value string) string {
+		return os.Expand(value, func(name string) string {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+	}
+	expanded := map[string]interface{}{}
+	for key, value := range config {
+		attributes, ok := value.(map[string]interface{})
+		secret, hasSecret := identityProviderSecrets[key]
+		if ok && hasSecret {
+			copied := map[string]interface{}{}
+			for attribute, attributeValue := range attributes {
+				copied[attribute] = attributeValue
+			}
+			if secretValue, ok := copied[secret].(string); ok {
+				missingBefore := len(missing)
+				copied[secret] = expand(secretValue)
+				if !requireSecrets && len(missing) > missingBefore {
+					missing = missing[:missingBefore]
+					delete(copied, secret)
+				}
+			}
+			value = copied
+		}
+		expanded[key] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Expected environment variables %s to be set with the secrets of '%v'",
+			missing, config["name"])
+	}
+	b, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, err
+	}
+	return cmv1.UnmarshalIdentityProvider(b)
+}
+
+// isSecretSet returns true when all the environment variables referenced by the secret are set.
+func isSecretSet(value string) bool {
+	set := true
+	os.Expand(value, func(name string) string {
+		_, ok := os.LookupEnv(name)
+		set = set && ok
+		return ""
+	})
+	return set
+}
+
+var placeholderInvalidChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+func secretPlaceholder(idpName string, secret string) string {
+	name := strings.ToUpper(fmt.Sprintf("%s_%s", idpName, secret))
+	return fmt.Sprintf("${%s}", placeholderInvalidChars.ReplaceAllString(name, "_"))
+}
+
+func NewIngressConfig(ingress *cmv1.Ingress) *IngressConfig {
+	return &IngressConfig{
+		Default:        ingress.Default(),
+		Private:        ingress.Listening() == cmv1.ListeningMethodInternal,
+		RouteSelectors: ingress.RouteSelectors(),
+	}
+}
+
+// Build returns the ingress described by the configuration, with the given identifier.
+func (i *IngressConfig) Build(id string) (*cmv1.Ingress, error) {
+	builder := cmv1.NewIngress()
+	if id != "" {
+		builder = builder.ID(id)
+	} else {
+		builder = builder.Default(i.Default)
+	}
+	if i.Private {
+		builder = builder.Listening(cmv1.ListeningMethodInternal)
+	} else {
+		builder = builder.Listening(cmv1.ListeningMethodExternal)
+	}
+	routeSelectors := i.RouteSelectors
+	if routeSelectors == nil {
+		routeSelectors = map[string]string{}
+	}
+	builder = builder.RouteSelectors(routeSelectors)
+	return builder.Build()
+}
+
+func NewAddOnConfig(addOn *cmv1.AddOnInstallation) *AddOnConfig {
+	config := &AddOnConfig{
+		ID: addOn.Addon().ID(),
+	}
+	addOn.Parameters().Each(func(param *cmv1.AddOnInstallationParameter) bool {
+		if config.Parameters == nil {
+			config.Parameters = map[string]string{}
+		}
+		config.Parameters[param.ID()] = param.Value()
+		return true
+	})
+	return config
+}
+
+// Params returns the parameters of the add-on sorted by name.
+func (a *AddOnConfig) Params() []AddOnParam {
+	params := []AddOnParam{}
+	for key, val := range a.Parameters {
+		params = append(params, AddOnParam{Key: key, Val: val})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+	return params
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package ocm
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -160,24 +159,23 @@ func (c *Client) CreateCluster(config Spec) (*cmv1.Cluster, error) {
 	return clusterObject, nil
 }
 
-func (c *Client) GetClusters(creator *aws.Creator, count int) (clusters []*cmv1.Cluster, err error) {
-	if count < 1 {
-		err = errors.New("Cannot fetch fewer than 1 cluster")
-		return
-	}
+// FindClusters returns all the clusters of the current AWS account that match the given
+// search query, using the same syntax as the OCM search parameter.
+func (c *Client) FindClusters(creator *aws.Creator, search string) (clusters []*cmv1.Cluster, err error) {
 	query := getClusterFilter(creator)
+	if search != "" {
+		query = fmt.Sprintf("%s AND (%s)", query, search)
+	}
 	request := c.ocm.ClustersMgmt().V1().Clusters().List().Search(query)
 	page := 1
+	size := 100
 	for {
-		response, err := request.Page(page).Size(count).Send()
+		response, err := request.Page(page).Size(size).Send()
 		if err != nil {
-			return clusters, err
+			return nil, handleErr(response.Error(), err)
 		}
-		response.Items().Each(func(cluster *cmv1.Cluster) bool {
-			clusters = append(clusters, cluster)
-			return true
-		})
-		if response.Size() != count {
+		clusters = append(clusters, response.Items().Slice()...)
+		if response.Size() < size {
 			break
 		}
 		page++
@@ -236,6 +234,18 @@ func (c *Client) GetClusterStatus(clusterID string) (*cmv1.ClusterStatus, error)
 	return response.Body(), nil
 }
 
+func (c *Client) GetClusterCredentials(clusterID string) (*cmv1.ClusterCredentials, error) {
+	response, err := c.ocm.ClustersMgmt().V1().Clusters().
+		Cluster(clusterID).
+		Credentials().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
 func (c *Client) GetClusterState(clusterID string) (cmv1.ClusterState, error) {
 	response, err := c.ocm.ClustersMgmt().V1().Clusters().
 		Cluster(clusterID).
@@ -0,0 +1,36 @@
+package ocm
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Unexported functions used by the tests of the package
+var (
+	DiffValues     = diffValues
+	EqualMaps      = equalMaps
+	EqualTaints    = equalTaints
+	PrintTaints    = printTaints
+	UpdatedSecrets = updatedSecrets
+)
+
+// MatchIngresses returns the identifier of the current ingress matched by each desired ingress,
+// empty for the ones that would be created, and the identifiers of the unmatched ingresses.
+func MatchIngresses(current []*cmv1.Ingress, desired []*IngressConfig) ([]string, []string, error) {
+	matches, unmatched, err := matchIngresses(current, desired)
+	if err != nil {
+		return nil, nil, err
+	}
+	matched := []string{}
+	for _, match := range matches {
+		id := ""
+		if match.current != nil {
+			id = match.current.ID()
+		}
+		matched = append(matched, id)
+	}
+	unmatchedIDs := []string{}
+	for _, ingress := range unmatched {
+		unmatchedIDs = append(unmatchedIDs, ingress.ID())
+	}
+	return matched, unmatchedIDs, nil
+}
@@ -0,0 +1,24 @@
+package ocm_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("GenerateRandomPassword", func() {
+	It("generates passwords with dashes at fixed positions", func() {
+		password, err := ocm.GenerateRandomPassword(23)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password).To(MatchRegexp(`^[a-zA-Z2-9]{5}-[a-zA-Z2-9]{5}-[a-zA-Z2-9]{5}-[a-zA-Z2-9]{5}$`))
+	})
+
+	It("generates different passwords", func() {
+		first, err := ocm.GenerateRandomPassword(23)
+		Expect(err).ToNot(HaveOccurred())
+		second, err := ocm.GenerateRandomPassword(23)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first).ToNot(Equal(second))
+	})
+})
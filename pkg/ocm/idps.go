@@ -17,6 +17,9 @@ limitations under the License.
 package ocm
 
 import (
+	"fmt"
+	"strings"
+
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
@@ -45,6 +48,18 @@ func (c *Client) CreateIdentityProvider(clusterID string, idp *cmv1.IdentityProv
 	return response.Body(), nil
 }
 
+func (c *Client) UpdateIdentityProvider(clusterID string, idp *cmv1.IdentityProvider) (*cmv1.IdentityProvider, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		IdentityProviders().IdentityProvider(idp.ID()).
+		Update().Body(idp).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
 func (c *Client) DeleteIdentityProvider(clusterID string, idpID string) error {
 	response, err := c.ocm.ClustersMgmt().V1().
 		Clusters().Cluster(clusterID).
@@ -75,3 +90,10 @@ func IdentityProviderType(idp *cmv1.IdentityProvider) string {
 
 	return ""
 }
+
+// GetOAuthCallbackURL returns the URL that must be registered with the provider so that it can
+// redirect users back to the cluster after they log in.
+func GetOAuthCallbackURL(cluster *cmv1.Cluster, idpName string) string {
+	oauthURL := strings.Replace(cluster.Console().URL(), "console-openshift-console", "oauth-openshift", 1)
+	return fmt.Sprintf("%s/oauth2callback/%s", oauthURL, idpName)
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package ocm
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
@@ -66,3 +70,192 @@ func (c *Client) DeleteMachinePool(clusterID string, machinePoolID string) error
 	}
 	return nil
 }
+
+// DefaultMachinePool returns the compute nodes of the cluster represented as a machine pool, so
+// that they can be shown together with the additional machine pools.
+func DefaultMachinePool(cluster *cmv1.Cluster) *cmv1.MachinePool {
+	builder := cmv1.NewMachinePool().
+		ID("Default").
+		AvailabilityZones(cluster.Nodes().AvailabilityZones()...).
+		InstanceType(cluster.Nodes().ComputeMachineType().ID()).
+		Labels(cluster.Nodes().ComputeLabels()).
+		Replicas(cluster.Nodes().Compute())
+	if cluster.Nodes().AutoscaleCompute() != nil {
+		builder = builder.Autoscaling(
+			cmv1.NewMachinePoolAutoscaling().
+				MinReplicas(cluster.Nodes().AutoscaleCompute().MinReplicas()).
+				MaxReplicas(cluster.Nodes().AutoscaleCompute().MaxReplicas()),
+		)
+	}
+	machinePool, _ := builder.Build()
+	return machinePool
+}
+
+// ParseLabels parses a comma-separated list of 'key=value' labels.
+func ParseLabels(labels string) (map[string]string, error) {
+	labelMap := map[string]string{}
+	labels = strings.TrimSpace(labels)
+	if labels == "" {
+		return labelMap, nil
+	}
+	for _, label := range strings.Split(labels, ",") {
+		if !strings.Contains(label, "=") {
+			return nil, fmt.Errorf("Expected key=value format for labels")
+		}
+		tokens := strings.SplitN(label, "=", 2)
+		key := strings.TrimSpace(tokens[0])
+		if key == "" {
+			return nil, fmt.Errorf("Expected key=value format for labels")
+		}
+		labelMap[key] = strings.TrimSpace(tokens[1])
+	}
+	return labelMap, nil
+}
+
+// ParseTaints parses a comma-separated list of 'key=value:effect' taints.
+func ParseTaints(taints string) ([]*cmv1.Taint, error) {
+	result := []*cmv1.Taint{}
+	taints = strings.TrimSpace(taints)
+	if taints == "" {
+		return result, nil
+	}
+	for _, taint := range strings.Split(taints, ",") {
+		colon := strings.LastIndex(taint, ":")
+		if !strings.Contains(taint, "=") || colon == -1 {
+			return nil, fmt.Errorf("Expected key=value:scheduleType format for taints")
+		}
+		tokens := strings.SplitN(taint[:colon], "=", 2)
+		key := strings.TrimSpace(tokens[0])
+		effect := strings.TrimSpace(taint[colon+1:])
+		if key == "" || effect == "" {
+			return nil, fmt.Errorf("Expected key=value:scheduleType format for taints")
+		}
+		item, err := cmv1.NewTaint().
+			Key(key).
+			Value(strings.TrimSpace(tokens[1])).
+			Effect(effect).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// ParseKeys parses a comma-separated list of label or taint keys, ignoring empty items.
+func ParseKeys(keys string) []string {
+	result := []string{}
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// MergeLabels returns the current labels with the given labels added or replaced and the given
+// keys removed. Removing a label that doesn't exist is an error, so that typos aren't ignored.
+func MergeLabels(current map[string]string, add map[string]string,
+	remove []string) (map[string]string, error) {
+	result := map[string]string{}
+	for key, value := range current {
+		result[key] = value
+	}
+	for _, key := range remove {
+		if _, ok := add[key]; ok {
+			return nil, fmt.Errorf("Label '%s' can't be both added and removed", key)
+		}
+		if _, ok := result[key]; !ok {
+			return nil, fmt.Errorf("Label '%s' doesn't exist", key)
+		}
+		delete(result, key)
+	}
+	for key, value := range add {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// MergeTaints returns the current taints with the given taints added and the given taints
+// removed. Taints are identified by key and effect, so adding a taint replaces the value of an
+// existing one with the same key and effect. Taints to remove are given as 'key', which removes
+// the taints with that key regardless of their effect, or as 'key:effect'. Removing a taint that
+// doesn't exist is an error, so that typos aren't ignored.
+func MergeTaints(current []*cmv1.Taint, add []*cmv1.Taint, remove []string) ([]*cmv1.Taint, error) {
+	result := []*cmv1.Taint{}
+	matched := map[string]bool{}
+	for _, taint := range current {
+		removed := false
+		for _, key := range remove {
+			if key == taint.Key() || key == fmt.Sprintf("%s:%s", taint.Key(), taint.Effect()) {
+				removed = true
+				matched[key] = true
+			}
+		}
+		if !removed {
+			result = append(result, taint)
+		}
+	}
+	for _, key := range remove {
+		if !matched[key] {
+			return nil, fmt.Errorf("Taint '%s' doesn't exist", key)
+		}
+	}
+	for _, taint := range add {
+		replaced := false
+		for i, item := range result {
+			if item.Key() == taint.Key() && item.Effect() == taint.Effect() {
+				result[i] = taint
+				replaced = true
+			}
+		}
+		if !replaced {
+			result = append(result, taint)
+		}
+	}
+	return result, nil
+}
+
+// FormatLabels returns the labels as sorted 'key=value' strings.
+func FormatLabels(labels map[string]string) []string {
+	result := []string{}
+	for key, value := range labels {
+		result = append(result, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// FormatTaints returns the taints as sorted 'key=value:effect' strings.
+func FormatTaints(taints []*cmv1.Taint) []string {
+	result := []string{}
+	for _, taint := range taints {
+		result = append(result, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// DiffItems compares two sorted lists of items and returns one line per item, prefixed with '-'
+// if it was removed, '+' if it was added or a space if it didn't change.
+func DiffItems(before []string, after []string) []string {
+	lines := []string{}
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case j == len(after) || (i < len(before) && before[i] < after[j]):
+			lines = append(lines, "- "+before[i])
+			i++
+		case i == len(before) || after[j] < before[i]:
+			lines = append(lines, "+ "+after[j])
+			j++
+		default:
+			lines = append(lines, "  "+before[i])
+			i++
+			j++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,126 @@
+package ocm_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+func taints(list string) []*cmv1.Taint {
+	result, err := ocm.ParseTaints(list)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+var _ = Describe("Machine pools", func() {
+	Context("ParseLabels", func() {
+		It("parses a list of labels", func() {
+			labels, err := ocm.ParseLabels("foo=bar, baz=a=b,empty=")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(labels).To(Equal(map[string]string{"foo": "bar", "baz": "a=b", "empty": ""}))
+		})
+
+		It("returns no labels for an empty list", func() {
+			labels, err := ocm.ParseLabels(" ")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(labels).To(BeEmpty())
+		})
+
+		It("rejects labels without a value", func() {
+			_, err := ocm.ParseLabels("foo=bar,baz")
+			Expect(err).To(MatchError("Expected key=value format for labels"))
+		})
+	})
+
+	Context("ParseTaints", func() {
+		It("parses a list of taints", func() {
+			result := taints("dedicated=gpu:NoSchedule,example.com/infra=:NoExecute")
+			Expect(ocm.FormatTaints(result)).To(Equal([]string{
+				"dedicated=gpu:NoSchedule",
+				"example.com/infra=:NoExecute",
+			}))
+		})
+
+		DescribeTable("rejects invalid taints",
+			func(list string) {
+				_, err := ocm.ParseTaints(list)
+				Expect(err).To(MatchError("Expected key=value:scheduleType format for taints"))
+			},
+			Entry("without effect", "dedicated=gpu"),
+			Entry("without value", "dedicated:NoSchedule"),
+			Entry("with empty effect", "dedicated=gpu:"),
+		)
+	})
+
+	Context("MergeLabels", func() {
+		current := map[string]string{"foo": "bar", "tier": "infra"}
+
+		It("adds, replaces and removes labels", func() {
+			labels, err := ocm.MergeLabels(current,
+				map[string]string{"tier": "gpu", "new": "yes"},
+				[]string{"foo"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(labels).To(Equal(map[string]string{"tier": "gpu", "new": "yes"}))
+			Expect(current).To(HaveLen(2))
+		})
+
+		It("rejects removing a label that doesn't exist", func() {
+			_, err := ocm.MergeLabels(current, nil, []string{"fo"})
+			Expect(err).To(MatchError("Label 'fo' doesn't exist"))
+		})
+
+		It("rejects adding and removing the same label", func() {
+			_, err := ocm.MergeLabels(current, map[string]string{"foo": "baz"}, []string{"foo"})
+			Expect(err).To(MatchError("Label 'foo' can't be both added and removed"))
+		})
+	})
+
+	Context("MergeTaints", func() {
+		var current []*cmv1.Taint
+		BeforeEach(func() {
+			current = taints("dedicated=gpu:NoSchedule,dedicated=gpu:NoExecute,infra=:NoSchedule")
+		})
+
+		It("replaces taints with the same key and effect", func() {
+			result, err := ocm.MergeTaints(current, taints("dedicated=ml:NoSchedule,new=x:NoSchedule"), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ocm.FormatTaints(result)).To(Equal([]string{
+				"dedicated=gpu:NoExecute",
+				"dedicated=ml:NoSchedule",
+				"infra=:NoSchedule",
+				"new=x:NoSchedule",
+			}))
+		})
+
+		It("removes taints by key", func() {
+			result, err := ocm.MergeTaints(current, nil, []string{"dedicated"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ocm.FormatTaints(result)).To(Equal([]string{"infra=:NoSchedule"}))
+		})
+
+		It("removes taints by key and effect", func() {
+			result, err := ocm.MergeTaints(current, nil, []string{"dedicated:NoExecute"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ocm.FormatTaints(result)).To(Equal([]string{
+				"dedicated=gpu:NoSchedule",
+				"infra=:NoSchedule",
+			}))
+		})
+
+		It("rejects removing a taint that doesn't exist", func() {
+			_, err := ocm.MergeTaints(current, nil, []string{"dedicated:PreferNoSchedule"})
+			Expect(err).To(MatchError("Taint 'dedicated:PreferNoSchedule' doesn't exist"))
+		})
+	})
+
+	Context("DiffItems", func() {
+		It("marks added, removed and unchanged items", func() {
+			lines := ocm.DiffItems([]string{"a=1", "b=2", "c=3"}, []string{"a=1", "b=3", "c=3", "d=4"})
+			Expect(lines).To(Equal([]string{"  a=1", "- b=2", "+ b=3", "  c=3", "+ d=4"}))
+		})
+	})
+})
@@ -17,8 +17,12 @@ limitations under the License.
 package ocm
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 
 	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
@@ -121,6 +125,25 @@ type MachineType struct {
 	AvailableQuota int
 }
 
+// MarshalJSON writes the machine type using the JSON representation of the OCM API, together
+// with its availability for the current organization.
+func (m *MachineType) MarshalJSON() ([]byte, error) {
+	var machineType bytes.Buffer
+	err := cmv1.MarshalMachineType(m.MachineType, &machineType)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		MachineType    json.RawMessage `json:"machine_type"`
+		Available      bool            `json:"available"`
+		AvailableQuota int             `json:"available_quota"`
+	}{
+		MachineType:    machineType.Bytes(),
+		Available:      m.Available,
+		AvailableQuota: m.AvailableQuota,
+	})
+}
+
 func (c *Client) GetAvailableMachineTypes() ([]*MachineType, error) {
 	machineTypes, err := c.GetMachineTypes()
 	if err != nil {
@@ -171,3 +194,187 @@ func (c *Client) GetAvailableMachineTypes() ([]*MachineType, error) {
 	}
 	return availableMachineTypes, nil
 }
+
+// Resources that OpenShift reserves on every node for the system, and the default maximum number
+// of pods per node. They are subtracted from the capacity of the instance types when estimating
+// how many nodes a workload needs.
+const (
+	NodeReservedCPU    = 0.5
+	NodeReservedMemory = 1 << 30
+	NodeMaxPods        = 250
+)
+
+// MachineTypeCategories maps the short names accepted on the command line to the categories of
+// machine types.
+var MachineTypeCategories = map[string]cmv1.MachineTypeCategory{
+	"general":     cmv1.MachineTypeCategoryGeneralPurpose,
+	"memory":      cmv1.MachineTypeCategoryMemoryOptimized,
+	"compute":     cmv1.MachineTypeCategoryComputeOptimized,
+	"accelerated": cmv1.MachineTypeCategoryAcceleratedComputing,
+}
+
+// ParseMachineTypeCategory returns the machine type category for the given short name or full
+// category name.
+func ParseMachineTypeCategory(category string) (cmv1.MachineTypeCategory, error) {
+	if value, ok := MachineTypeCategories[category]; ok {
+		return value, nil
+	}
+	for _, value := range MachineTypeCategories {
+		if string(value) == category {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid category '%s': expected one of 'general', 'memory', "+
+		"'compute' or 'accelerated'", category)
+}
+
+// MachineTypeFilter contains the criteria used to select machine types. Zero values don't
+// filter anything.
+type MachineTypeFilter struct {
+	MinCPU    int
+	MinMemory float64
+	Category  cmv1.MachineTypeCategory
+	MultiAZ   bool
+}
+
+// FilterMachineTypes returns the available machine types that match the filter. With MultiAZ,
+// accelerated machine types are only returned if there is quota for a multi-AZ machine pool.
+func FilterMachineTypes(machineTypes []*MachineType, filter MachineTypeFilter) []*MachineType {
+	result := []*MachineType{}
+	for _, v := range machineTypes {
+		if !v.Available {
+			continue
+		}
+		if v.MachineType.CPU().Value() < float64(filter.MinCPU) ||
+			v.MachineType.Memory().Value() < filter.MinMemory {
+			continue
+		}
+		if filter.Category != "" && v.MachineType.Category() != filter.Category {
+			continue
+		}
+		if filter.MultiAZ && v.MachineType.Category() == AcceleratedComputing &&
+			v.AvailableQuota < getDefaultNodes(true) {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// SortMachineTypes sorts the machine types by 'id', 'category', 'cpu' or 'memory'. Machine types
+// with the same value are sorted by identifier.
+func SortMachineTypes(machineTypes []*MachineType, by string) error {
+	var less func(a, b *cmv1.MachineType) bool
+	switch by {
+	case "id":
+		less = func(a, b *cmv1.MachineType) bool { return false }
+	case "category":
+		less = func(a, b *cmv1.MachineType) bool { return a.Category() < b.Category() }
+	case "cpu":
+		less = func(a, b *cmv1.MachineType) bool { return a.CPU().Value() < b.CPU().Value() }
+	case "memory":
+		less = func(a, b *cmv1.MachineType) bool { return a.Memory().Value() < b.Memory().Value() }
+	default:
+		return fmt.Errorf("Invalid sort key '%s': expected one of 'id', 'category', 'cpu' or 'memory'", by)
+	}
+	sort.SliceStable(machineTypes, func(i, j int) bool {
+		a := machineTypes[i].MachineType
+		b := machineTypes[j].MachineType
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.ID() < b.ID()
+	})
+	return nil
+}
+
+// Workload describes the total resources that the nodes of a machine pool need to provide.
+type Workload struct {
+	CPU     float64
+	Memory  float64
+	Pods    int
+	MultiAZ bool
+}
+
+// Recommendation is a machine type together with the number of replicas that fit a workload.
+type Recommendation struct {
+	MachineType *MachineType
+	Replicas    int
+}
+
+// RecommendMachineTypes returns, for each of the given machine types, the number of replicas
+// needed to run the workload. They are sorted so that the first one needs the fewest nodes, and
+// among those, provides the least CPU and memory. Multi-AZ workloads use a multiple of three
+// replicas so that they are spread evenly across zones.
+func RecommendMachineTypes(machineTypes []*MachineType, workload Workload) []*Recommendation {
+	result := []*Recommendation{}
+	for _, v := range machineTypes {
+		cpu := v.MachineType.CPU().Value() - NodeReservedCPU
+		memory := v.MachineType.Memory().Value() - NodeReservedMemory
+		if cpu <= 0 || memory <= 0 {
+			continue
+		}
+		replicas := 1
+		replicas = maxInt(replicas, int(math.Ceil(workload.CPU/cpu)))
+		replicas = maxInt(replicas, int(math.Ceil(workload.Memory/memory)))
+		replicas = maxInt(replicas, (workload.Pods+NodeMaxPods-1)/NodeMaxPods)
+		if workload.MultiAZ && replicas%3 != 0 {
+			replicas += 3 - replicas%3
+		}
+		if v.MachineType.Category() == AcceleratedComputing && v.AvailableQuota < replicas {
+			continue
+		}
+		result = append(result, &Recommendation{
+			MachineType: v,
+			Replicas:    replicas,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a := result[i]
+		b := result[j]
+		if a.Replicas != b.Replicas {
+			return a.Replicas < b.Replicas
+		}
+		cpuA := a.MachineType.MachineType.CPU().Value() * float64(a.Replicas)
+		cpuB := b.MachineType.MachineType.CPU().Value() * float64(b.Replicas)
+		if cpuA != cpuB {
+			return cpuA < cpuB
+		}
+		memoryA := a.MachineType.MachineType.Memory().Value() * float64(a.Replicas)
+		memoryB := b.MachineType.MachineType.Memory().Value() * float64(b.Replicas)
+		if memoryA != memoryB {
+			return memoryA < memoryB
+		}
+		return a.MachineType.MachineType.ID() < b.MachineType.MachineType.ID()
+	})
+	return result
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ByteCountIEC returns the given amount of memory in the largest binary unit that fits it, for
+// example '16.0 GiB'. Amounts that aren't in bytes are returned as they are.
+func ByteCountIEC(b int, uValue string) string {
+	if uValue != "B" {
+		return fmt.Sprintf("%d %s", b, uValue)
+	}
+	unit := 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= int64(unit)
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB",
+		float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,155 @@
+package ocm_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+const gib = 1 << 30
+
+func machineType(id string, category cmv1.MachineTypeCategory, cpu float64, memory float64,
+	quota int) *ocm.MachineType {
+	item, err := cmv1.NewMachineType().
+		ID(id).
+		Category(category).
+		CPU(cmv1.NewValue().Value(cpu).Unit("vCPU")).
+		Memory(cmv1.NewValue().Value(memory * gib).Unit("B")).
+		Build()
+	Expect(err).ToNot(HaveOccurred())
+	return &ocm.MachineType{
+		MachineType:    item,
+		Available:      category != ocm.AcceleratedComputing || quota > 1,
+		AvailableQuota: quota,
+	}
+}
+
+func ids(machineTypes []*ocm.MachineType) []string {
+	result := []string{}
+	for _, v := range machineTypes {
+		result = append(result, v.MachineType.ID())
+	}
+	return result
+}
+
+var _ = Describe("Machine types", func() {
+	var machineTypes []*ocm.MachineType
+
+	BeforeEach(func() {
+		machineTypes = []*ocm.MachineType{
+			machineType("m5.xlarge", cmv1.MachineTypeCategoryGeneralPurpose, 4, 16, 0),
+			machineType("m5.2xlarge", cmv1.MachineTypeCategoryGeneralPurpose, 8, 32, 0),
+			machineType("r5.xlarge", cmv1.MachineTypeCategoryMemoryOptimized, 4, 32, 0),
+			machineType("c5.4xlarge", cmv1.MachineTypeCategoryComputeOptimized, 16, 32, 0),
+			machineType("g4dn.xlarge", cmv1.MachineTypeCategoryAcceleratedComputing, 4, 16, 2),
+			machineType("p3.2xlarge", cmv1.MachineTypeCategoryAcceleratedComputing, 8, 61, 0),
+		}
+	})
+
+	Context("ParseMachineTypeCategory", func() {
+		It("accepts short and full names", func() {
+			category, err := ocm.ParseMachineTypeCategory("memory")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category).To(Equal(cmv1.MachineTypeCategoryMemoryOptimized))
+			category, err = ocm.ParseMachineTypeCategory("compute_optimized")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category).To(Equal(cmv1.MachineTypeCategoryComputeOptimized))
+		})
+
+		It("rejects unknown categories", func() {
+			_, err := ocm.ParseMachineTypeCategory("storage")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("FilterMachineTypes", func() {
+		It("skips unavailable machine types", func() {
+			result := ocm.FilterMachineTypes(machineTypes, ocm.MachineTypeFilter{})
+			Expect(ids(result)).To(Equal([]string{
+				"m5.xlarge", "m5.2xlarge", "r5.xlarge", "c5.4xlarge", "g4dn.xlarge",
+			}))
+		})
+
+		It("filters by CPU, memory and category", func() {
+			result := ocm.FilterMachineTypes(machineTypes, ocm.MachineTypeFilter{
+				MinCPU:    8,
+				MinMemory: 32 * gib,
+				Category:  cmv1.MachineTypeCategoryGeneralPurpose,
+			})
+			Expect(ids(result)).To(Equal([]string{"m5.2xlarge"}))
+		})
+
+		It("skips accelerated machine types without quota for multi-AZ", func() {
+			result := ocm.FilterMachineTypes(machineTypes, ocm.MachineTypeFilter{MultiAZ: true})
+			Expect(ids(result)).ToNot(ContainElement("g4dn.xlarge"))
+		})
+	})
+
+	Context("SortMachineTypes", func() {
+		It("sorts by memory and then by identifier", func() {
+			err := ocm.SortMachineTypes(machineTypes, "memory")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ids(machineTypes)).To(Equal([]string{
+				"g4dn.xlarge", "m5.xlarge", "c5.4xlarge", "m5.2xlarge", "r5.xlarge", "p3.2xlarge",
+			}))
+		})
+
+		It("rejects unknown sort keys", func() {
+			err := ocm.SortMachineTypes(machineTypes, "price")
+			Expect(err).To(MatchError(ContainSubstring("Invalid sort key 'price'")))
+		})
+	})
+
+	Context("RecommendMachineTypes", func() {
+		BeforeEach(func() {
+			machineTypes = machineTypes[:4]
+		})
+
+		It("recommends the fewest nodes with the least resources", func() {
+			result := ocm.RecommendMachineTypes(machineTypes, ocm.Workload{CPU: 3, Memory: 8 * gib})
+			Expect(result[0].MachineType.MachineType.ID()).To(Equal("m5.xlarge"))
+			Expect(result[0].Replicas).To(Equal(1))
+		})
+
+		It("accounts for the resources reserved on each node", func() {
+			result := ocm.RecommendMachineTypes(machineTypes, ocm.Workload{CPU: 15.6})
+			Expect(result[0].MachineType.MachineType.ID()).To(Equal("c5.4xlarge"))
+			Expect(result[0].Replicas).To(Equal(2))
+		})
+
+		It("uses the memory of the nodes", func() {
+			result := ocm.RecommendMachineTypes(machineTypes, ocm.Workload{CPU: 4, Memory: 60 * gib})
+			Expect(result[0].MachineType.MachineType.ID()).To(Equal("r5.xlarge"))
+			Expect(result[0].Replicas).To(Equal(2))
+		})
+
+		It("limits the number of pods per node", func() {
+			result := ocm.RecommendMachineTypes(machineTypes, ocm.Workload{Pods: 600})
+			Expect(result[0].MachineType.MachineType.ID()).To(Equal("m5.xlarge"))
+			Expect(result[0].Replicas).To(Equal(3))
+		})
+
+		It("uses a multiple of three replicas for multi-AZ", func() {
+			result := ocm.RecommendMachineTypes(machineTypes, ocm.Workload{CPU: 20, MultiAZ: true})
+			for _, recommendation := range result {
+				Expect(recommendation.Replicas % 3).To(BeZero())
+			}
+			Expect(result[0].MachineType.MachineType.ID()).To(Equal("m5.2xlarge"))
+			Expect(result[0].Replicas).To(Equal(3))
+		})
+	})
+
+	DescribeTable("ByteCountIEC",
+		func(amount int, unit string, expected string) {
+			Expect(ocm.ByteCountIEC(amount, unit)).To(Equal(expected))
+		},
+		Entry("bytes", 512, "B", "512 B"),
+		Entry("kibibytes", 1536, "B", "1.5 KiB"),
+		Entry("gibibytes", 16*gib, "B", "16.0 GiB"),
+		Entry("other units", 16, "GiB", "16 GiB"),
+	)
+})
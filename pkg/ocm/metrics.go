@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func (c *Client) GetClusterOperators(clusterID string) ([]*cmv1.ClusterOperatorInfo, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		MetricQueries().ClusterOperators().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body().Operators(), nil
+}
+
+func (c *Client) GetAlerts(clusterID string) ([]*cmv1.AlertInfo, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		MetricQueries().Alerts().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body().Alerts(), nil
+}
+
+func (c *Client) GetNodes(clusterID string) ([]*cmv1.NodeInfo, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		MetricQueries().Nodes().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body().Nodes(), nil
+}
+
+func (c *Client) GetCPUTotals(clusterID string) ([]*cmv1.CPUTotalNodeRoleOSMetricNode, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		MetricQueries().CPUTotalByNodeRolesOS().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body().CPUTotals(), nil
+}
+
+func (c *Client) GetSocketTotals(clusterID string) ([]*cmv1.SocketTotalNodeRoleOSMetricNode, error) {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		MetricQueries().SocketTotalByNodeRolesOS().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body().SocketTotals(), nil
+}
+
+// provisionErrorHints maps fragments of provision error codes and messages to suggestions on how
+// to fix the underlying problem. They are checked in order, so more specific fragments go first.
+var provisionErrorHints = []struct {
+	fragment string
+	hint     string
+}{
+	{"vcpulimitexceeded", "The AWS account has reached its vCPU limit. Request an increase of the " +
+		"running On-Demand instances quota in the AWS Service Quotas console"},
+	{"quota", "The AWS account doesn't have enough quota. Run 'rosa verify quota' to find the " +
+		"services that need an increase, and request it in the AWS Service Quotas console"},
+	{"explicit deny", "An AWS Organizations service control policy is denying a required action. " +
+		"Run 'rosa verify permissions' and review the SCPs attached to the account"},
+	{"unauthorizedoperation", "The installer role is missing permissions. Run 'rosa verify " +
+		"permissions' and check the account roles"},
+	{"accessdenied", "The installer role is missing permissions. Run 'rosa verify permissions' " +
+		"and check the account roles"},
+	{"subnet", "The subnets given at install time are invalid. Check that they exist, have free " +
+		"addresses and belong to the selected availability zones"},
+	{"hosted zone", "The Route 53 hosted zone could not be configured. Check that there are no " +
+		"conflicting hosted zones for the cluster domain"},
+	{"dns", "The cluster DNS records could not be resolved. Check the VPC DNS settings and any " +
+		"custom DHCP options"},
+	{"timeout", "The installation timed out. Run 'rosa logs install' to find the step that " +
+		"didn't complete"},
+}
+
+// GetProvisionErrorHint returns a human-readable suggestion for the given provision error.
+func GetProvisionErrorHint(clusterKey string, code string, message string) string {
+	text := strings.ToLower(code + " " + message)
+	for _, item := range provisionErrorHints {
+		if strings.Contains(text, item.fragment) {
+			return item.hint
+		}
+	}
+	return fmt.Sprintf("Run 'rosa logs install -c %s' to see the installation logs", clusterKey)
+}
@@ -0,0 +1,40 @@
+package ocm_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("GetProvisionErrorHint", func() {
+	DescribeTable("maps provision errors to hints",
+		func(code string, message string, expected string) {
+			Expect(ocm.GetProvisionErrorHint("mycluster", code, message)).To(ContainSubstring(expected))
+		},
+		Entry("vCPU limit", "OCM3055", "VcpuLimitExceeded: You have requested more vCPU capacity",
+			"running On-Demand instances quota in the AWS Service Quotas console"),
+		Entry("quota before generic matches", "", "Insufficient quota for subnet resources",
+			"Run 'rosa verify quota'"),
+		Entry("service control policy", "", "with an explicit deny in a service control policy",
+			"service control policy"),
+		Entry("unauthorized operation", "UnauthorizedOperation", "",
+			"The installer role is missing permissions"),
+		Entry("access denied", "", "AccessDenied: User is not authorized",
+			"The installer role is missing permissions"),
+		Entry("subnets", "", "The subnet ID 'subnet-123' does not exist",
+			"The subnets given at install time are invalid"),
+		Entry("hosted zone", "", "Conflicting hosted zone exists", "Route 53 hosted zone"),
+		Entry("DNS", "", "DNS resolution failed", "VPC DNS settings"),
+		Entry("timeout", "OCM3003", "Installation timeout", "The installation timed out"),
+		Entry("unknown errors", "OCM9999", "Something else went wrong",
+			"Run 'rosa logs install -c mycluster' to see the installation logs"),
+	)
+
+	It("doesn't point quota errors to 'verify quota' for increases", func() {
+		hint := ocm.GetProvisionErrorHint("mycluster", "", "quota exceeded")
+		Expect(hint).To(ContainSubstring("AWS Service Quotas console"))
+		Expect(hint).ToNot(ContainSubstring("'rosa verify quota' to request"))
+	})
+})
@@ -0,0 +1,13 @@
+package ocm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOCM(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OCM Suite")
+}
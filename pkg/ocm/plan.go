@@ -0,0 +1,692 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to compare the day-2 configuration of a cluster with a
+// desired configuration, and to apply the changes needed to reconcile them.
+
+package ocm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+type ChangeAction string
+
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+)
+
+// Change is a single operation needed to reconcile a cluster with its desired configuration.
+type Change struct {
+	Action  ChangeAction
+	Kind    string
+	Name    string
+	Details []string
+	apply   func() error
+}
+
+// String returns a one line description of the change, prefixed with '+', '~' or '-'.
+func (c *Change) String() string {
+	prefix := map[ChangeAction]string{
+		ChangeCreate: "+",
+		ChangeUpdate: "~",
+		ChangeDelete: "-",
+	}[c.Action]
+	return fmt.Sprintf("%s %s %s '%s'", prefix, c.Action, c.Kind, c.Name)
+}
+
+// Apply executes the change against the cluster.
+func (c *Change) Apply() error {
+	return c.apply()
+}
+
+// FormatChanges returns the description of the changes, one per line followed by its details, and
+// a summary of the number of changes of each kind.
+func FormatChanges(changes []*Change) string {
+	var b strings.Builder
+	counts := map[ChangeAction]int{}
+	for _, change := range changes {
+		counts[change.Action]++
+		fmt.Fprintf(&b, "%s\n", change)
+		for _, detail := range change.Details {
+			fmt.Fprintf(&b, "    %s\n", detail)
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		counts[ChangeCreate], counts[ChangeUpdate], counts[ChangeDelete])
+	return b.String()
+}
+
+// PlanClusterConfig returns the changes needed to make the cluster match the desired configuration.
+// Resources that exist in the cluster but not in the configuration are only deleted when prune is
+// set. The admin identity provider and user are never deleted, and the upgrade policy isn't
+// managed.
+func (c *Client) PlanClusterConfig(cluster *cmv1.Cluster, creator *aws.Creator, desired *ClusterConfig,
+	prune bool) ([]*Change, error) {
+	changes := []*Change{}
+
+	machinePoolChanges, err := c.planMachinePools(cluster, desired.MachinePools, prune)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, machinePoolChanges...)
+
+	idpChanges, err := c.planIdentityProviders(cluster, desired.IdentityProviders, prune)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, idpChanges...)
+
+	ingressChanges, err := c.planIngresses(cluster, desired.Ingresses, prune)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, ingressChanges...)
+
+	addOnChanges, err := c.planAddOns(cluster, creator, desired.AddOns, prune)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, addOnChanges...)
+
+	userChanges, err := c.planUsers(cluster, desired.Users, prune)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, userChanges...)
+
+	return changes, nil
+}
+
+func (c *Client) planMachinePools(cluster *cmv1.Cluster, desired []*MachinePoolConfig,
+	prune bool) ([]*Change, error) {
+	current, err := c.GetMachinePools(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get machine pools: %v", err)
+	}
+	currentByID := map[string]*MachinePoolConfig{}
+	for _, machinePool := range current {
+		currentByID[machinePool.ID()] = NewMachinePoolConfig(machinePool)
+	}
+
+	changes := []*Change{}
+	desiredIDs := map[string]bool{}
+	for _, machinePoolConfig := range desired {
+		desiredIDs[machinePoolConfig.ID] = true
+		machinePool, err := machinePoolConfig.Build()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read machine pool '%s': %v", machinePoolConfig.ID, err)
+		}
+
+		existing, ok := currentByID[machinePoolConfig.ID]
+		if !ok {
+			changes = append(changes, &Change{
+				Action: ChangeCreate,
+				Kind:   "machine pool",
+				Name:   machinePoolConfig.ID,
+				apply: func() error {
+					_, err := c.CreateMachinePool(cluster.ID(), machinePool)
+					return err
+				},
+			})
+			continue
+		}
+
+		if existing.InstanceType != machinePoolConfig.InstanceType {
+			return nil, fmt.Errorf("Instance type of machine pool '%s' can't be changed from '%s' to '%s'",
+				existing.ID, existing.InstanceType, machinePoolConfig.InstanceType)
+		}
+		details := []string{}
+		if existing.Autoscaling == nil && machinePoolConfig.Autoscaling == nil {
+			if existing.Replicas != machinePoolConfig.Replicas {
+				details = append(details, describeChange("replicas", existing.Replicas, machinePoolConfig.Replicas))
+			}
+		} else if !reflect.DeepEqual(existing.Autoscaling, machinePoolConfig.Autoscaling) {
+			details = append(details, describeChange("autoscaling",
+				printAutoscaling(existing), printAutoscaling(machinePoolConfig)))
+		}
+		if !equalMaps(existing.Labels, machinePoolConfig.Labels) {
+			details = append(details, describeChange("labels", existing.Labels, machinePoolConfig.Labels))
+		}
+		if !equalTaints(existing.Taints, machinePoolConfig.Taints) {
+			details = append(details, describeChange("taints",
+				printTaints(existing.Taints), printTaints(machinePoolConfig.Taints)))
+		}
+		if len(details) == 0 {
+			continue
+		}
+		// The instance type and availability zones can't be sent in updates
+		machinePool, err = machinePoolConfig.buildUpdate()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read machine pool '%s': %v", machinePoolConfig.ID, err)
+		}
+		changes = append(changes, &Change{
+			Action:  ChangeUpdate,
+			Kind:    "machine pool",
+			Name:    machinePoolConfig.ID,
+			Details: details,
+			apply: func() error {
+				_, err := c.UpdateMachinePool(cluster.ID(), machinePool)
+				return err
+			},
+		})
+	}
+
+	if prune {
+		for _, machinePool := range current {
+			id := machinePool.ID()
+			if desiredIDs[id] {
+				continue
+			}
+			changes = append(changes, &Change{
+				Action: ChangeDelete,
+				Kind:   "machine pool",
+				Name:   id,
+				apply: func() error {
+					return c.DeleteMachinePool(cluster.ID(), id)
+				},
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func (c *Client) planIdentityProviders(cluster *cmv1.Cluster, desired []map[string]interface{},
+	prune bool) ([]*Change, error) {
+	current, err := c.GetIdentityProviders(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get identity providers: %v", err)
+	}
+	currentByName := map[string]*cmv1.IdentityProvider{}
+	for _, idp := range current {
+		currentByName[idp.Name()] = idp
+	}
+
+	changes := []*Change{}
+	desiredNames := map[string]bool{}
+	for _, idpConfig := range desired {
+		name, _ := idpConfig["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("Expected a name for every identity provider")
+		}
+		if name == AdminIdentityProvider {
+			return nil, fmt.Errorf("Identity provider '%s' is managed by 'rosa create admin'", name)
+		}
+		desiredNames[name] = true
+
+		existing, ok := currentByName[name]
+		if !ok {
+			idp, err := BuildIdentityProvider(idpConfig)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read identity provider '%s': %v", name, err)
+			}
+			changes = append(changes, &Change{
+				Action: ChangeCreate,
+				Kind:   "identity provider",
+				Name:   name,
+				apply: func() error {
+					_, err := c.CreateIdentityProvider(cluster.ID(), idp)
+					return err
+				},
+			})
+			continue
+		}
+
+		// Secrets aren't returned by the API, so only the other attributes can be compared.
+		// Secrets whose environment variable isn't set are left unchanged, and the ones that are
+		// set are always updated.
+		idp, err := buildIdentityProvider(idpConfig, false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read identity provider '%s': %v", name, err)
+		}
+		if IdentityProviderType(idp) != IdentityProviderType(existing) {
+			return nil, fmt.Errorf("Type of identity provider '%s' can't be changed from '%s' to '%s'",
+				name, IdentityProviderType(existing), IdentityProviderType(idp))
+		}
+		existingConfig, err := NewIdentityProviderConfig(existing)
+		if err != nil {
+			return nil, err
+		}
+		normalizedConfig, err := NewIdentityProviderConfig(idp)
+		if err != nil {
+			return nil, err
+		}
+		if normalizedConfig["mapping_method"] == nil {
+			normalizedConfig["mapping_method"] = existingConfig["mapping_method"]
+		}
+		details := diffValues("", existingConfig, normalizedConfig)
+		details = append(details, updatedSecrets(idpConfig)...)
+		if len(details) == 0 {
+			continue
+		}
+		idp, err = buildIdentityProvider(mergeID(idpConfig, existing.ID()), false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read identity provider '%s': %v", name, err)
+		}
+		changes = append(changes, &Change{
+			Action:  ChangeUpdate,
+			Kind:    "identity provider",
+			Name:    name,
+			Details: details,
+			apply: func() error {
+				_, err := c.UpdateIdentityProvider(cluster.ID(), idp)
+				return err
+			},
+		})
+	}
+
+	if prune {
+		for _, idp := range current {
+			name := idp.Name()
+			id := idp.ID()
+			if desiredNames[name] || name == AdminIdentityProvider {
+				continue
+			}
+			changes = append(changes, &Change{
+				Action: ChangeDelete,
+				Kind:   "identity provider",
+				Name:   name,
+				apply: func() error {
+					return c.DeleteIdentityProvider(cluster.ID(), id)
+				},
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func (c *Client) planIngresses(cluster *cmv1.Cluster, desired []*IngressConfig, prune bool) ([]*Change, error) {
+	current, err := c.GetIngresses(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get ingresses: %v", err)
+	}
+	matches, unmatched, err := matchIngresses(current, desired)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to match ingresses of cluster '%s': %v", cluster.Name(), err)
+	}
+
+	changes := []*Change{}
+	for _, match := range matches {
+		if match.current != nil {
+			name := "default"
+			if !match.current.Default() {
+				name = match.current.ID()
+			}
+			change, err := c.planIngressUpdate(cluster, match.current, match.desired, name)
+			if err != nil {
+				return nil, err
+			}
+			if change != nil {
+				changes = append(changes, change)
+			}
+			continue
+		}
+		ingress, err := match.desired.Build("")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read ingress: %v", err)
+		}
+		changes = append(changes, &Change{
+			Action: ChangeCreate,
+			Kind:   "ingress",
+			Name:   "additional",
+			apply: func() error {
+				_, err := c.CreateIngress(cluster.ID(), ingress)
+				return err
+			},
+		})
+	}
+
+	if prune {
+		for _, ingress := range unmatched {
+			id := ingress.ID()
+			changes = append(changes, &Change{
+				Action: ChangeDelete,
+				Kind:   "ingress",
+				Name:   id,
+				apply: func() error {
+					return c.DeleteIngress(cluster.ID(), id)
+				},
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// ingressMatch is a desired ingress and the current ingress that it updates, if any.
+type ingressMatch struct {
+	current *cmv1.Ingress
+	desired *IngressConfig
+}
+
+// matchIngresses pairs each desired ingress with the current ingress that it updates, and returns
+// the additional ingresses that no desired ingress matches. The default ingress always exists,
+// additional ingresses are matched in order of identifier since their identifiers are generated.
+func matchIngresses(current []*cmv1.Ingress, desired []*IngressConfig) ([]*ingressMatch, []*cmv1.Ingress,
+	error) {
+	var currentDefault *cmv1.Ingress
+	currentAdditional := []*cmv1.Ingress{}
+	for _, ingress := range current {
+		if ingress.Default() {
+			currentDefault = ingress
+		} else {
+			currentAdditional = append(currentAdditional, ingress)
+		}
+	}
+	sort.Slice(currentAdditional, func(i, j int) bool {
+		return currentAdditional[i].ID() < currentAdditional[j].ID()
+	})
+
+	matches := []*ingressMatch{}
+	additional := 0
+	for _, ingressConfig := range desired {
+		if ingressConfig.Default {
+			if currentDefault == nil {
+				return nil, nil, fmt.Errorf("There is no default ingress")
+			}
+			matches = append(matches, &ingressMatch{current: currentDefault, desired: ingressConfig})
+			continue
+		}
+		match := &ingressMatch{desired: ingressConfig}
+		if additional < len(currentAdditional) {
+			match.current = currentAdditional[additional]
+		}
+		additional++
+		matches = append(matches, match)
+	}
+
+	unmatched := []*cmv1.Ingress{}
+	if additional < len(currentAdditional) {
+		unmatched = currentAdditional[additional:]
+	}
+	return matches, unmatched, nil
+}
+
+func (c *Client) planIngressUpdate(cluster *cmv1.Cluster, current *cmv1.Ingress, desired *IngressConfig,
+	name string) (*Change, error) {
+	existing := NewIngressConfig(current)
+	details := []string{}
+	if existing.Private != desired.Private {
+		details = append(details, describeChange("private", existing.Private, desired.Private))
+	}
+	if !equalMaps(existing.RouteSelectors, desired.RouteSelectors) {
+		details = append(details, describeChange("route_selectors", existing.RouteSelectors, desired.RouteSelectors))
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+	ingress, err := desired.Build(current.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read ingress: %v", err)
+	}
+	return &Change{
+		Action:  ChangeUpdate,
+		Kind:    "ingress",
+		Name:    name,
+		Details: details,
+		apply: func() error {
+			_, err := c.UpdateIngress(cluster.ID(), ingress)
+			return err
+		},
+	}, nil
+}
+
+func (c *Client) planAddOns(cluster *cmv1.Cluster, creator *aws.Creator, desired []*AddOnConfig,
+	prune bool) ([]*Change, error) {
+	current, err := c.GetAddOnInstallations(cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get add-ons: %v", err)
+	}
+	currentByID := map[string]*AddOnConfig{}
+	for _, addOn := range current {
+		currentByID[addOn.Addon().ID()] = NewAddOnConfig(addOn)
+	}
+
+	changes := []*Change{}
+	desiredIDs := map[string]bool{}
+	for _, addOnConfig := range desired {
+		id := addOnConfig.ID
+		params := addOnConfig.Params()
+		desiredIDs[id] = true
+		existing, ok := currentByID[id]
+		if !ok {
+			changes = append(changes, &Change{
+				Action: ChangeCreate,
+				Kind:   "add-on",
+				Name:   id,
+				apply: func() error {
+					return c.InstallAddOn(cluster.ID(), creator, id, params)
+				},
+			})
+			continue
+		}
+		if equalMaps(existing.Parameters, addOnConfig.Parameters) {
+			continue
+		}
+		changes = append(changes, &Change{
+			Action:  ChangeUpdate,
+			Kind:    "add-on",
+			Name:    id,
+			Details: []string{describeChange("parameters", existing.Parameters, addOnConfig.Parameters)},
+			apply: func() error {
+				return c.UpdateAddOnInstallation(cluster.ID(), creator, id, params)
+			},
+		})
+	}
+
+	if prune {
+		for _, addOn := range current {
+			id := addOn.Addon().ID()
+			if desiredIDs[id] {
+				continue
+			}
+			changes = append(changes, &Change{
+				Action: ChangeDelete,
+				Kind:   "add-on",
+				Name:   id,
+				apply: func() error {
+					return c.UninstallAddOn(cluster.ID(), creator, id)
+				},
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func (c *Client) planUsers(cluster *cmv1.Cluster, desired map[string][]string, prune bool) ([]*Change, error) {
+	for group := range desired {
+		valid := false
+		for _, userGroup := range UserGroups {
+			valid = valid || group == userGroup
+		}
+		if !valid {
+			return nil, fmt.Errorf("Expected users to be granted one of %s, got '%s'", UserGroups, group)
+		}
+	}
+
+	changes := []*Change{}
+	for _, group := range UserGroups {
+		group := group
+		current, err := c.GetUsers(cluster.ID(), group)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get users of group '%s': %v", group, err)
+		}
+		currentIDs := map[string]bool{}
+		for _, user := range current {
+			currentIDs[user.ID()] = true
+		}
+		desiredIDs := map[string]bool{}
+		for _, username := range desired[group] {
+			desiredIDs[username] = true
+			if currentIDs[username] {
+				continue
+			}
+			if !IsValidUsername(username) || username == AdminUsername {
+				return nil, fmt.Errorf("Username '%s' isn't valid", username)
+			}
+			user, err := cmv1.NewUser().ID(username).Build()
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, &Change{
+				Action: ChangeCreate,
+				Kind:   fmt.Sprintf("user of group '%s'", group),
+				Name:   username,
+				apply: func() error {
+					_, err := c.CreateUser(cluster.ID(), group, user)
+					return err
+				},
+			})
+		}
+		if prune {
+			for _, user := range current {
+				username := user.ID()
+				if desiredIDs[username] || username == AdminUsername {
+					continue
+				}
+				changes = append(changes, &Change{
+					Action: ChangeDelete,
+					Kind:   fmt.Sprintf("user of group '%s'", group),
+					Name:   username,
+					apply: func() error {
+						return c.DeleteUser(cluster.ID(), group, username)
+					},
+				})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func mergeID(config map[string]interface{}, id string) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range config {
+		merged[key] = value
+	}
+	merged["id"] = id
+	return merged
+}
+
+// updatedSecrets returns the details of the secrets of the identity provider configuration that
+// will be sent, which are the ones whose environment variables are set.
+func updatedSecrets(config map[string]interface{}) []string {
+	details := []string{}
+	for key, secret := range identityProviderSecrets {
+		attributes, ok := config[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, ok := attributes[secret].(string)
+		if !ok || !isSecretSet(value) {
+			continue
+		}
+		details = append(details, describeChange(key+"."+secret, "(hidden)", "(new value)"))
+	}
+	sort.Strings(details)
+	return details
+}
+
+// diffValues returns the paths of the values that differ between the two JSON documents.
+func diffValues(path string, current interface{}, desired interface{}) []string {
+	currentMap, currentIsMap := current.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	if !currentIsMap || !desiredIsMap {
+		if reflect.DeepEqual(current, desired) {
+			return nil
+		}
+		return []string{describeChange(path, jsonValue(current), jsonValue(desired))}
+	}
+	keys := map[string]bool{}
+	for key := range currentMap {
+		keys[key] = true
+	}
+	for key := range desiredMap {
+		keys[key] = true
+	}
+	sortedKeys := []string{}
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	details := []string{}
+	for _, key := range sortedKeys {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		details = append(details, diffValues(keyPath, currentMap[key], desiredMap[key])...)
+	}
+	return details
+}
+
+func jsonValue(value interface{}) string {
+	if value == nil {
+		return "none"
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
+
+func describeChange(name string, current interface{}, desired interface{}) string {
+	return fmt.Sprintf("%s: %v -> %v", name, current, desired)
+}
+
+func equalMaps(a map[string]string, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func equalTaints(a []*TaintConfig, b []*TaintConfig) bool {
+	return printTaints(a) == printTaints(b)
+}
+
+func printTaints(taints []*TaintConfig) string {
+	values := []string{}
+	for _, taint := range taints {
+		values = append(values, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+	}
+	sort.Strings(values)
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ",")
+}
+
+func printAutoscaling(machinePool *MachinePoolConfig) string {
+	if machinePool.Autoscaling == nil {
+		return fmt.Sprintf("off (%d replicas)", machinePool.Replicas)
+	}
+	return fmt.Sprintf("%d-%d replicas", machinePool.Autoscaling.MinReplicas, machinePool.Autoscaling.MaxReplicas)
+}
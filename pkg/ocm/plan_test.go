@@ -0,0 +1,162 @@
+package ocm_test
+
+import (
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+func ingress(id string, isDefault bool) *cmv1.Ingress {
+	item, err := cmv1.NewIngress().ID(id).Default(isDefault).Build()
+	Expect(err).ToNot(HaveOccurred())
+	return item
+}
+
+var _ = Describe("Plan", func() {
+	Context("DiffValues", func() {
+		It("returns nothing for equal documents", func() {
+			current := map[string]interface{}{
+				"name":   "github-1",
+				"github": map[string]interface{}{"teams": []interface{}{"a/b"}},
+			}
+			desired := map[string]interface{}{
+				"name":   "github-1",
+				"github": map[string]interface{}{"teams": []interface{}{"a/b"}},
+			}
+			Expect(ocm.DiffValues("", current, desired)).To(BeEmpty())
+		})
+
+		It("returns the sorted paths of the changed values", func() {
+			current := map[string]interface{}{
+				"mapping_method": "claim",
+				"github": map[string]interface{}{
+					"hostname": "github.com",
+					"teams":    []interface{}{"a/b"},
+				},
+			}
+			desired := map[string]interface{}{
+				"mapping_method": "lookup",
+				"github": map[string]interface{}{
+					"organizations": []interface{}{"myorg"},
+					"hostname":      "github.com",
+				},
+			}
+			Expect(ocm.DiffValues("", current, desired)).To(Equal([]string{
+				`github.organizations: none -> ["myorg"]`,
+				`github.teams: ["a/b"] -> none`,
+				`mapping_method: "claim" -> "lookup"`,
+			}))
+		})
+
+		It("compares a value with an object", func() {
+			Expect(ocm.DiffValues("ldap", "none", map[string]interface{}{"url": "ldap://a"})).To(Equal([]string{
+				`ldap: "none" -> {"url":"ldap://a"}`,
+			}))
+		})
+	})
+
+	DescribeTable("EqualMaps",
+		func(a map[string]string, b map[string]string, expected bool) {
+			Expect(ocm.EqualMaps(a, b)).To(Equal(expected))
+		},
+		Entry("nil and empty", nil, map[string]string{}, true),
+		Entry("equal", map[string]string{"a": "b"}, map[string]string{"a": "b"}, true),
+		Entry("different value", map[string]string{"a": "b"}, map[string]string{"a": "c"}, false),
+		Entry("missing key", map[string]string{"a": "b"}, map[string]string{}, false),
+	)
+
+	Context("Taints", func() {
+		a := &ocm.TaintConfig{Key: "a", Value: "1", Effect: "NoSchedule"}
+		b := &ocm.TaintConfig{Key: "b", Value: "", Effect: "NoExecute"}
+
+		It("prints sorted taints", func() {
+			Expect(ocm.PrintTaints([]*ocm.TaintConfig{b, a})).To(Equal("a=1:NoSchedule,b=:NoExecute"))
+		})
+
+		It("prints no taints as none", func() {
+			Expect(ocm.PrintTaints(nil)).To(Equal("none"))
+		})
+
+		It("ignores the order of taints", func() {
+			Expect(ocm.EqualTaints([]*ocm.TaintConfig{a, b}, []*ocm.TaintConfig{b, a})).To(BeTrue())
+		})
+
+		It("compares the effect of taints", func() {
+			c := &ocm.TaintConfig{Key: "a", Value: "1", Effect: "NoExecute"}
+			Expect(ocm.EqualTaints([]*ocm.TaintConfig{a}, []*ocm.TaintConfig{c})).To(BeFalse())
+		})
+	})
+
+	Context("MatchIngresses", func() {
+		var current []*cmv1.Ingress
+
+		BeforeEach(func() {
+			current = []*cmv1.Ingress{
+				ingress("c", false),
+				ingress("a", true),
+				ingress("b", false),
+			}
+		})
+
+		It("matches additional ingresses in order of identifier", func() {
+			matched, unmatched, err := ocm.MatchIngresses(current, []*ocm.IngressConfig{
+				{Private: true},
+				{Default: true},
+				{},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matched).To(Equal([]string{"b", "a", "c"}))
+			Expect(unmatched).To(BeEmpty())
+		})
+
+		It("creates the additional ingresses that don't exist", func() {
+			matched, unmatched, err := ocm.MatchIngresses(current, []*ocm.IngressConfig{{}, {}, {}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matched).To(Equal([]string{"b", "c", ""}))
+			Expect(unmatched).To(BeEmpty())
+		})
+
+		It("returns the additional ingresses that aren't desired", func() {
+			matched, unmatched, err := ocm.MatchIngresses(current, []*ocm.IngressConfig{{Default: true}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matched).To(Equal([]string{"a"}))
+			Expect(unmatched).To(Equal([]string{"b", "c"}))
+		})
+
+		It("fails when there is no default ingress", func() {
+			_, _, err := ocm.MatchIngresses(current[:1], []*ocm.IngressConfig{{Default: true}})
+			Expect(err).To(MatchError("There is no default ingress"))
+		})
+	})
+
+	Context("UpdatedSecrets", func() {
+		config := map[string]interface{}{
+			"name": "github-1",
+			"github": map[string]interface{}{
+				"client_id":     "abc",
+				"client_secret": "${ROSA_TEST_GITHUB_SECRET}",
+			},
+		}
+
+		AfterEach(func() {
+			os.Unsetenv("ROSA_TEST_GITHUB_SECRET")
+		})
+
+		It("doesn't update secrets whose variable isn't set", func() {
+			Expect(ocm.UpdatedSecrets(config)).To(BeEmpty())
+		})
+
+		It("updates secrets whose variable is set", func() {
+			os.Setenv("ROSA_TEST_GITHUB_SECRET", "secret")
+			Expect(ocm.UpdatedSecrets(config)).To(Equal([]string{
+				"github.client_secret: (hidden) -> (new value)",
+			}))
+		})
+	})
+})
@@ -17,9 +17,71 @@ limitations under the License.
 package ocm
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
+// NodeDrainOptions are the valid values for the node drain grace period of a cluster
+var NodeDrainOptions = []string{
+	"15 minutes",
+	"30 minutes",
+	"45 minutes",
+	"1 hour",
+	"2 hours",
+	"4 hours",
+	"8 hours",
+}
+
+// GetNodeDrainGracePeriod returns the node drain grace period of the cluster in the same format
+// as the node drain options, or an empty string if it isn't set.
+func GetNodeDrainGracePeriod(cluster *cmv1.Cluster) string {
+	nd := cluster.NodeDrainGracePeriod()
+	if _, ok := nd.GetValue(); !ok {
+		return ""
+	}
+	// Convert larger times to hours, since the API only stores minutes
+	val := int(nd.Value())
+	unit := nd.Unit()
+	if val >= 60 {
+		val = val / 60
+		if val == 1 {
+			unit = "hour"
+		} else {
+			unit = "hours"
+		}
+	}
+	return fmt.Sprintf("%d %s", val, unit)
+}
+
+// ParseNodeDrainGracePeriod validates the given node drain grace period against the node drain
+// options and returns its value in minutes.
+func ParseNodeDrainGracePeriod(nodeDrainGracePeriod string) (float64, error) {
+	isValidNodeDrainGracePeriod := false
+	for _, nodeDrainOption := range NodeDrainOptions {
+		if nodeDrainGracePeriod == nodeDrainOption {
+			isValidNodeDrainGracePeriod = true
+			break
+		}
+	}
+	if !isValidNodeDrainGracePeriod {
+		return 0, fmt.Errorf("Expected a valid node drain grace period. Options are [%s]",
+			strings.Join(NodeDrainOptions, ", "))
+	}
+	nodeDrainParsed := strings.Split(nodeDrainGracePeriod, " ")
+	nodeDrainValue, err := strconv.ParseFloat(nodeDrainParsed[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("Expected a valid node drain grace period: %s", err)
+	}
+	if nodeDrainParsed[1] == "hours" || nodeDrainParsed[1] == "hour" {
+		nodeDrainValue = nodeDrainValue * 60
+	}
+	return nodeDrainValue, nil
+}
+
 func (c *Client) GetUpgradePolicies(clusterID string) (upgradePolicies []*cmv1.UpgradePolicy, err error) {
 	collection := c.ocm.ClustersMgmt().V1().
 		Clusters().
@@ -69,6 +131,95 @@ func (c *Client) GetScheduledUpgrade(clusterID string) (*cmv1.UpgradePolicy, *cm
 	return nil, nil, nil
 }
 
+// UpgradeVersionTimeout is how long an upgrade is considered pending after its policy has been
+// removed, while the cluster doesn't yet report the new version.
+const UpgradeVersionTimeout = 30 * time.Minute
+
+// GetUpgradeState returns the state of the upgrade of the cluster to the given version. Manual
+// upgrade policies are removed once they complete or get cancelled, so when there is no policy
+// for that version the state is derived from the version that the cluster is running. As the
+// policy is removed before the version of the cluster is refreshed, an upgrade without policy to
+// a version that the cluster doesn't run yet is reported as pending.
+func (c *Client) GetUpgradeState(clusterID string, version string) (*cmv1.UpgradePolicyState, error) {
+	state, _, err := c.getUpgradeState(clusterID, version)
+	return state, err
+}
+
+func (c *Client) getUpgradeState(clusterID string, version string) (state *cmv1.UpgradePolicyState,
+	scheduled bool, err error) {
+	scheduledUpgrade, upgradeState, err := c.GetScheduledUpgrade(clusterID)
+	if err != nil {
+		return nil, false, err
+	}
+	if scheduledUpgrade != nil && scheduledUpgrade.Version() == version {
+		return upgradeState, true, nil
+	}
+
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		Get().
+		Send()
+	if err != nil {
+		return nil, false, handleErr(response.Error(), err)
+	}
+
+	if response.Body().OpenshiftVersion() == version {
+		state, err = cmv1.NewUpgradePolicyState().
+			Value(cmv1.UpgradePolicyStateValueCompleted).
+			Description(fmt.Sprintf("Cluster is running version %s", version)).
+			Build()
+		return state, false, err
+	}
+	state, err = cmv1.NewUpgradePolicyState().
+		Value(cmv1.UpgradePolicyStateValuePending).
+		Description(fmt.Sprintf("Waiting for the cluster to report version %s", version)).
+		Build()
+	return state, false, err
+}
+
+// UpgradeWatch polls the state of the upgrade of a cluster to a version. Unlike GetUpgradeState
+// it reports the upgrade as cancelled when there has been no policy for the version, and the
+// cluster hasn't reported it, for longer than UpgradeVersionTimeout.
+type UpgradeWatch struct {
+	client       *Client
+	clusterID    string
+	version      string
+	missingSince time.Time
+}
+
+// WatchUpgrade returns an object that polls the state of the upgrade of the cluster to the given
+// version.
+func (c *Client) WatchUpgrade(clusterID string, version string) *UpgradeWatch {
+	return &UpgradeWatch{
+		client:    c,
+		clusterID: clusterID,
+		version:   version,
+	}
+}
+
+// State returns the current state of the upgrade.
+func (w *UpgradeWatch) State() (*cmv1.UpgradePolicyState, error) {
+	state, scheduled, err := w.client.getUpgradeState(w.clusterID, w.version)
+	if err != nil {
+		return nil, err
+	}
+	if scheduled || state.Value() != cmv1.UpgradePolicyStateValuePending {
+		w.missingSince = time.Time{}
+		return state, nil
+	}
+	if w.missingSince.IsZero() {
+		w.missingSince = time.Now()
+	}
+	if time.Since(w.missingSince) < UpgradeVersionTimeout {
+		return state, nil
+	}
+	return cmv1.NewUpgradePolicyState().
+		Value(cmv1.UpgradePolicyStateValueCancelled).
+		Description(fmt.Sprintf("There is no upgrade scheduled to version %s, and the cluster "+
+			"hasn't reported it after %s", w.version, UpgradeVersionTimeout)).
+		Build()
+}
+
 func (c *Client) ScheduleUpgrade(clusterID string, upgradePolicy *cmv1.UpgradePolicy) error {
 	response, err := c.ocm.ClustersMgmt().V1().
 		Clusters().Cluster(clusterID).
@@ -81,6 +232,18 @@ func (c *Client) ScheduleUpgrade(clusterID string, upgradePolicy *cmv1.UpgradePo
 	return nil
 }
 
+func (c *Client) UpdateUpgrade(clusterID string, upgradePolicy *cmv1.UpgradePolicy) error {
+	response, err := c.ocm.ClustersMgmt().V1().
+		Clusters().Cluster(clusterID).
+		UpgradePolicies().UpgradePolicy(upgradePolicy.ID()).
+		Update().Body(upgradePolicy).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
 func (c *Client) CancelUpgrade(clusterID string) (bool, error) {
 	scheduledUpgrade, _, err := c.GetScheduledUpgrade(clusterID)
 	if err != nil || scheduledUpgrade == nil {
@@ -138,6 +138,104 @@ func (c *Client) GetAvailableUpgrades(versionID string) ([]string, error) {
 	return availableUpgrades, nil
 }
 
+// GetUpgradeVersions returns the versions in the channel group of the cluster that form its
+// upgrade graph. The current version of the cluster is always included, even if it is no longer
+// enabled, as it is the starting point of the graph.
+func (c *Client) GetUpgradeVersions(cluster *cmv1.Cluster) ([]*cmv1.Version, error) {
+	versions, err := c.GetVersions(cluster.Version().ChannelGroup())
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		if version.RawID() == cluster.OpenshiftVersion() {
+			return versions, nil
+		}
+	}
+	response, err := c.ocm.ClustersMgmt().V1().
+		Versions().
+		Version(GetVersionID(cluster)).
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return append(versions, response.Body()), nil
+}
+
+// GetUpgradePath returns the shortest sequence of versions that the cluster needs to be upgraded
+// to in order to reach the target version, built from the available upgrades of all the versions
+// in the channel group of the cluster. The last element of the path is the target version.
+func (c *Client) GetUpgradePath(cluster *cmv1.Cluster, target string) ([]string, error) {
+	versions, err := c.GetUpgradeVersions(cluster)
+	if err != nil {
+		return nil, err
+	}
+	path := FindUpgradePath(versions, cluster.OpenshiftVersion(), target)
+	if path == nil {
+		return nil, fmt.Errorf("There is no upgrade path from version %s to version %s",
+			cluster.OpenshiftVersion(), target)
+	}
+	return path, nil
+}
+
+// FindUpgradePath does a breadth-first search on the upgrade graph formed by the given versions
+// and returns the shortest path between the two versions, excluding the source version. Returns
+// nil if the target version can't be reached.
+func FindUpgradePath(versions []*cmv1.Version, from string, to string) []string {
+	graph := GetUpgradeGraph(versions)
+	if _, ok := graph[to]; !ok || from == to {
+		return nil
+	}
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[current] {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = current
+			if next == to {
+				path := []string{}
+				for v := to; v != from; v = previous[v] {
+					path = append([]string{v}, path...)
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// GetUpgradeGraph returns the adjacency list of the upgrade graph formed by the given versions.
+// Upgrades to versions that aren't in the list, for example because they aren't enabled for
+// ROSA, are ignored. Edges are sorted so that the latest version shows up first.
+func GetUpgradeGraph(versions []*cmv1.Version) map[string][]string {
+	graph := map[string][]string{}
+	for _, version := range versions {
+		graph[version.RawID()] = []string{}
+	}
+	for _, version := range versions {
+		for _, upgrade := range version.AvailableUpgrades() {
+			if _, ok := graph[upgrade]; ok {
+				graph[version.RawID()] = append(graph[version.RawID()], upgrade)
+			}
+		}
+		edges := graph[version.RawID()]
+		sort.Slice(edges, func(i, j int) bool {
+			a, erra := ver.NewVersion(edges[i])
+			b, errb := ver.NewVersion(edges[j])
+			if erra != nil || errb != nil {
+				return edges[i] > edges[j]
+			}
+			return a.GreaterThan(b)
+		})
+	}
+	return graph
+}
+
 func createVersionID(version string, channelGroup string) string {
 	versionID := fmt.Sprintf("openshift-v%s", version)
 	if channelGroup != "stable" {
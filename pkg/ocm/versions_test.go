@@ -0,0 +1,74 @@
+package ocm_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+func version(id string, upgrades ...string) *cmv1.Version {
+	item, err := cmv1.NewVersion().
+		ID("openshift-v" + id).
+		RawID(id).
+		AvailableUpgrades(upgrades...).
+		Build()
+	Expect(err).ToNot(HaveOccurred())
+	return item
+}
+
+var _ = Describe("Versions", func() {
+	var versions []*cmv1.Version
+
+	BeforeEach(func() {
+		versions = []*cmv1.Version{
+			version("4.6.8", "4.6.9", "4.7.0"),
+			version("4.6.9", "4.6.10", "4.7.0", "4.7.1"),
+			version("4.6.10"),
+			version("4.7.0", "4.7.1"),
+			version("4.7.1", "4.8.0"),
+			version("4.8.0"),
+			version("4.9.0"),
+		}
+	})
+
+	Context("GetUpgradeGraph", func() {
+		It("sorts the edges with the latest version first", func() {
+			graph := ocm.GetUpgradeGraph(versions)
+			Expect(graph["4.6.9"]).To(Equal([]string{"4.7.1", "4.7.0", "4.6.10"}))
+			Expect(graph["4.9.0"]).To(BeEmpty())
+		})
+
+		It("ignores upgrades to versions that aren't in the list", func() {
+			graph := ocm.GetUpgradeGraph([]*cmv1.Version{version("4.6.8", "4.6.9", "4.7.0"), version("4.7.0")})
+			Expect(graph).To(HaveLen(2))
+			Expect(graph["4.6.8"]).To(Equal([]string{"4.7.0"}))
+		})
+	})
+
+	DescribeTable("FindUpgradePath",
+		func(from string, to string, expected []string) {
+			path := ocm.FindUpgradePath(versions, from, to)
+			if expected == nil {
+				Expect(path).To(BeNil())
+			} else {
+				Expect(path).To(Equal(expected))
+			}
+		},
+		Entry("direct hop", "4.6.8", "4.7.0", []string{"4.7.0"}),
+		Entry("multiple hops", "4.6.8", "4.8.0", []string{"4.7.0", "4.7.1", "4.8.0"}),
+		Entry("prefers the shortest path", "4.6.9", "4.8.0", []string{"4.7.1", "4.8.0"}),
+		Entry("unreachable version", "4.6.8", "4.9.0", nil),
+		Entry("version not in the list", "4.6.8", "4.10.0", nil),
+		Entry("same version", "4.7.0", "4.7.0", nil),
+		Entry("current version missing from the list", "4.6.7", "4.7.0", nil),
+	)
+
+	It("finds a path once the current version is added to the list", func() {
+		versions = append(versions, version("4.6.7", "4.6.8"))
+		Expect(ocm.FindUpgradePath(versions, "4.6.7", "4.7.0")).To(Equal([]string{"4.6.8", "4.7.0"}))
+	})
+})
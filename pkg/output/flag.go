@@ -26,7 +26,15 @@ import (
 
 var o string
 
-var formats = []string{"json", "yaml"}
+var formats = []string{
+	"json",
+	"yaml",
+	"csv",
+	"jsonpath=",
+	"go-template=",
+	"go-template-file=",
+	"custom-columns=",
+}
 
 // AddFlag adds the interactive flag to the given set of command line flags.
 func AddFlag(cmd *cobra.Command) {
@@ -35,14 +43,32 @@ func AddFlag(cmd *cobra.Command) {
 		"output",
 		"o",
 		"",
-		fmt.Sprintf("Output format. Allowed formats are %s", formats),
+		fmt.Sprintf("Output format. Allowed formats are %s. Formats ending in '=' take an argument, "+
+			"for example -o jsonpath='{.id}' or -o custom-columns=ID:.id,NAME:.name", formats),
 	)
 
 	cmd.RegisterFlagCompletionFunc("output", completion)
+
+	// Check the format before running the command, so that mistakes are reported before any
+	// call to the API:
+	preRunE := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, argv []string) error {
+		if o != "" {
+			err := validate(o)
+			if err != nil {
+				return err
+			}
+		}
+		if preRunE != nil {
+			return preRunE(cmd, argv)
+		}
+		return nil
+	}
 }
 
 func completion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	return formats, cobra.ShellCompDirectiveDefault
+	// Formats that take an argument shouldn't be followed by a space
+	return formats, cobra.ShellCompDirectiveNoSpace
 }
 
 func HasFlag() bool {
@@ -0,0 +1,277 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a small implementation of the JSONPath template syntax used by kubectl. It
+// supports text, string literals, field and index access, wildcards and range blocks, for
+// example '{range .items[*]}{.id}{"\t"}{.name}{"\n"}{end}'.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type jsonPathNode struct {
+	text     string
+	path     string
+	isPath   bool
+	children []*jsonPathNode
+	isRange  bool
+}
+
+// parseJSONPath parses the template into a tree of nodes. Templates without any braces are
+// considered to be a single path expression.
+func parseJSONPath(template string) ([]*jsonPathNode, error) {
+	if !strings.Contains(template, "{") {
+		template = fmt.Sprintf("{%s}", template)
+	}
+
+	root := &jsonPathNode{}
+	stack := []*jsonPathNode{root}
+	for len(template) > 0 {
+		parent := stack[len(stack)-1]
+		start := strings.Index(template, "{")
+		if start == -1 {
+			parent.children = append(parent.children, &jsonPathNode{text: template})
+			break
+		}
+		if start > 0 {
+			parent.children = append(parent.children, &jsonPathNode{text: template[:start]})
+		}
+		end := findActionEnd(template, start)
+		if end == -1 {
+			return nil, fmt.Errorf("Unclosed action in JSONPath template '%s'", template)
+		}
+		action := strings.TrimSpace(template[start+1 : end])
+		template = template[end+1:]
+
+		switch {
+		case action == "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("Unexpected '{end}' in JSONPath template")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(action, "range "):
+			node := &jsonPathNode{
+				path:    strings.TrimSpace(strings.TrimPrefix(action, "range ")),
+				isRange: true,
+			}
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case strings.HasPrefix(action, "\""):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid string literal %s in JSONPath template: %v", action, err)
+			}
+			parent.children = append(parent.children, &jsonPathNode{text: text})
+		default:
+			parent.children = append(parent.children, &jsonPathNode{path: action, isPath: true})
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("Missing '{end}' in JSONPath template")
+	}
+	return root.children, nil
+}
+
+// findActionEnd returns the index of the brace that closes the action starting at the given
+// index, ignoring braces inside string literals.
+func findActionEnd(template string, start int) int {
+	inString := false
+	for i := start + 1; i < len(template); i++ {
+		switch template[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '}':
+			if !inString {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// executeJSONPath evaluates the template against the given data, which is the result of
+// decoding a JSON document into an empty interface.
+func executeJSONPath(template string, data interface{}) (string, error) {
+	nodes, err := parseJSONPath(template)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	err = evalJSONPathNodes(&out, nodes, data, data)
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func evalJSONPathNodes(out *bytes.Buffer, nodes []*jsonPathNode, root interface{}, current interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			values, err := evalJSONPath(node.path, root, current)
+			if err != nil {
+				return err
+			}
+			for _, value := range values {
+				err = evalJSONPathNodes(out, node.children, root, value)
+				if err != nil {
+					return err
+				}
+			}
+		case node.isPath:
+			values, err := evalJSONPath(node.path, root, current)
+			if err != nil {
+				return err
+			}
+			for i, value := range values {
+				if i > 0 {
+					out.WriteString(" ")
+				}
+				out.WriteString(formatValue(value))
+			}
+		default:
+			out.WriteString(node.text)
+		}
+	}
+	return nil
+}
+
+// evalJSONPath returns all the values selected by the path. Missing fields select nothing
+// instead of failing, so that optional attributes can be printed.
+func evalJSONPath(path string, root interface{}, current interface{}) ([]interface{}, error) {
+	values := []interface{}{current}
+	switch {
+	case strings.HasPrefix(path, "$"):
+		values = []interface{}{root}
+		path = path[1:]
+	case strings.HasPrefix(path, "@"):
+		path = path[1:]
+	}
+
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end == -1 {
+				end = len(path)
+			}
+			field := path[:end]
+			path = path[end:]
+			if field == "" {
+				continue
+			}
+			next := []interface{}{}
+			for _, value := range values {
+				if field == "*" {
+					next = append(next, children(value)...)
+					continue
+				}
+				if object, ok := value.(map[string]interface{}); ok {
+					if child, ok := object[field]; ok {
+						next = append(next, child)
+					}
+				}
+			}
+			values = next
+		case '[':
+			end := strings.Index(path, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("Unclosed index in JSONPath expression")
+			}
+			index := strings.Trim(path[1:end], "'\"")
+			path = path[end+1:]
+			next := []interface{}{}
+			for _, value := range values {
+				if index == "*" {
+					next = append(next, children(value)...)
+					continue
+				}
+				switch typed := value.(type) {
+				case []interface{}:
+					i, err := strconv.Atoi(index)
+					if err != nil {
+						return nil, fmt.Errorf("Invalid array index '%s' in JSONPath expression", index)
+					}
+					if i < 0 {
+						i += len(typed)
+					}
+					if i >= 0 && i < len(typed) {
+						next = append(next, typed[i])
+					}
+				case map[string]interface{}:
+					if child, ok := typed[index]; ok {
+						next = append(next, child)
+					}
+				}
+			}
+			values = next
+		default:
+			return nil, fmt.Errorf("Invalid JSONPath expression '%s'", path)
+		}
+	}
+	return values, nil
+}
+
+// children returns the elements of an array or the values of an object sorted by key.
+func children(value interface{}) []interface{} {
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, typed[key])
+		}
+		return result
+	}
+	return nil
+}
+
+// formatValue returns the text representation of a value. Scalars are printed as is, while
+// objects and arrays are printed as JSON.
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number, bool, float64:
+		return fmt.Sprint(typed)
+	default:
+		b, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprint(typed)
+		}
+		return string(b)
+	}
+}
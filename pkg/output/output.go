@@ -23,10 +23,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"reflect"
+	"io/ioutil"
+	"strings"
+	"text/template"
 
 	"github.com/ghodss/yaml"
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"gitlab.com/c0b/go-ordered-json"
 )
 
@@ -36,57 +37,40 @@ import (
 // that the output can be shown correctly.
 var emptyBuffer = []byte{91, 10, 32, 32, 10, 93}
 
+// Print writes the resource to the standard output in the format given with the '--output'
+// command line option.
 func Print(resource interface{}) error {
+	str, err := Format(resource, o)
+	if err != nil {
+		return err
+	}
+	fmt.Print(str)
+	return nil
+}
+
+// Format returns the text representation of the resource in the given output format, for
+// example 'yaml' or 'jsonpath={.id}'.
+func Format(resource interface{}, output string) (string, error) {
 	var b bytes.Buffer
-	switch reflect.TypeOf(resource).String() {
-	case "[]*v1.CloudRegion":
-		if cloudRegions, ok := resource.([]*cmv1.CloudRegion); ok {
-			cmv1.MarshalCloudRegionList(cloudRegions, &b)
-		}
-	case "*v1.Cluster":
-		if cluster, ok := resource.(*cmv1.Cluster); ok {
-			cmv1.MarshalCluster(cluster, &b)
-		}
-	case "[]*v1.Cluster":
-		if clusters, ok := resource.([]*cmv1.Cluster); ok {
-			cmv1.MarshalClusterList(clusters, &b)
-		}
-	case "[]*v1.IdentityProvider":
-		if idps, ok := resource.([]*cmv1.IdentityProvider); ok {
-			cmv1.MarshalIdentityProviderList(idps, &b)
-		}
-	case "[]*v1.Ingress":
-		if ingresses, ok := resource.([]*cmv1.Ingress); ok {
-			cmv1.MarshalIngressList(ingresses, &b)
-		}
-	case "[]*v1.MachinePool":
-		if machinePools, ok := resource.([]*cmv1.MachinePool); ok {
-			cmv1.MarshalMachinePoolList(machinePools, &b)
-		}
-	case "[]*v1.MachineType":
-		if machineTypes, ok := resource.([]*cmv1.MachineType); ok {
-			cmv1.MarshalMachineTypeList(machineTypes, &b)
-		}
-	case "[]*v1.Version":
-		if versions, ok := resource.([]*cmv1.Version); ok {
-			cmv1.MarshalVersionList(versions, &b)
-		}
+	err := marshal(resource, &b)
+	if err != nil {
+		return "", err
 	}
 	// Verify if the resource is an empty string and ensure that the JSON
 	// representation looks correct for STDOUT.
 	if b.String() == string(emptyBuffer) {
 		b = *bytes.NewBufferString("[]")
 	}
-	str, err := parseResource(b)
-	if err != nil {
-		return err
-	}
-	fmt.Print(str)
-	return nil
+	return parseResource(b, output)
 }
 
-func parseResource(body bytes.Buffer) (string, error) {
-	switch o {
+func parseResource(body bytes.Buffer, output string) (string, error) {
+	err := validate(output)
+	if err != nil {
+		return "", err
+	}
+	format, arg := parseFormat(output)
+	switch format {
 	case "json":
 		var out bytes.Buffer
 		prettifyJSON(&out, body.Bytes())
@@ -97,9 +81,95 @@ func parseResource(body bytes.Buffer) (string, error) {
 			return "", err
 		}
 		return string(out), nil
+	case "csv":
+		return printCSV(body.Bytes())
+	}
+
+	// Keep numbers as they are written, instead of converting them to floating point and
+	// printing large ones in scientific notation
+	var data interface{}
+	decoder := json.NewDecoder(&body)
+	decoder.UseNumber()
+	err = decoder.Decode(&data)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case "jsonpath":
+		return executeJSONPath(arg, data)
+	case "custom-columns":
+		return printCustomColumns(arg, data)
+	case "go-template-file":
+		content, err := ioutil.ReadFile(arg)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read template file '%s': %v", arg, err)
+		}
+		arg = string(content)
+		fallthrough
+	case "go-template":
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return "", fmt.Errorf("Failed to parse template: %v", err)
+		}
+		var out bytes.Buffer
+		err = tmpl.Execute(&out, data)
+		if err != nil {
+			return "", fmt.Errorf("Failed to execute template: %v", err)
+		}
+		return out.String(), nil
 	default:
-		return "", fmt.Errorf("Unknown format '%s'. Valid formats are %s", o, formats)
+		return "", fmt.Errorf("Unknown format '%s'. Valid formats are %s", output, formats)
+	}
+}
+
+// validate checks that the output format is known and that the formats that take an argument
+// have a valid one, so that mistakes are reported before loading the resource.
+func validate(output string) error {
+	format, arg := parseFormat(output)
+	switch format {
+	case "json", "yaml", "csv":
+		if strings.Contains(output, "=") {
+			return fmt.Errorf("Format '%s' doesn't take an argument", format)
+		}
+		return nil
+	case "jsonpath":
+		if arg == "" {
+			return fmt.Errorf("Expected a JSONPath expression, for example -o jsonpath='{.id}'")
+		}
+		_, err := parseJSONPath(arg)
+		return err
+	case "custom-columns":
+		if arg == "" {
+			return fmt.Errorf("Expected a list of columns, for example -o custom-columns=ID:.id,NAME:.name")
+		}
+		_, _, err := parseCustomColumns(arg)
+		return err
+	case "go-template":
+		if arg == "" {
+			return fmt.Errorf("Expected a template, for example -o go-template='{{.id}}'")
+		}
+		_, err := template.New("output").Parse(arg)
+		if err != nil {
+			return fmt.Errorf("Failed to parse template: %v", err)
+		}
+		return nil
+	case "go-template-file":
+		if arg == "" {
+			return fmt.Errorf("Expected the path of a template file, for example -o go-template-file=cluster.tmpl")
+		}
+		return nil
+	}
+	return fmt.Errorf("Unknown format '%s'. Valid formats are %s", output, formats)
+}
+
+// parseFormat splits an output format such as 'jsonpath={.id}' into the name of the format
+// and its argument.
+func parseFormat(output string) (string, string) {
+	parts := strings.SplitN(output, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
 	}
+	return parts[0], parts[1]
 }
 
 func prettifyJSON(stream io.Writer, body []byte) error {
@@ -0,0 +1,149 @@
+package output_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/output"
+)
+
+// Clusters used as the resource to print, written as JSON so that the order of the attributes
+// is preserved.
+const clusters = `[
+  {
+    "kind": "Cluster",
+    "id": "abc",
+    "name": "prod",
+    "nodes": {"compute": 3, "memory": 17179869184},
+    "labels": {"team": "a", "env": "prod"},
+    "zones": ["us-east-1a", "us-east-1b"],
+    "hibernating": false
+  },
+  {
+    "kind": "Cluster",
+    "id": "def",
+    "name": "dev",
+    "nodes": {"compute": 1000000, "memory": 0.5},
+    "zones": []
+  }
+]`
+
+var _ = Describe("Output", func() {
+	var resource json.RawMessage
+
+	BeforeEach(func() {
+		resource = json.RawMessage(clusters)
+	})
+
+	format := func(format string) string {
+		str, err := output.Format(resource, format)
+		Expect(err).ToNot(HaveOccurred())
+		return str
+	}
+
+	Context("jsonpath", func() {
+		DescribeTable("evaluates templates",
+			func(template string, expected string) {
+				Expect(format("jsonpath=" + template)).To(Equal(expected))
+			},
+			Entry("field", "{[0].name}", "prod"),
+			Entry("bare path without braces", "[1].id", "def"),
+			Entry("root path", "{$[0].id}", "abc"),
+			Entry("wildcard index", "{[*].name}", "prod dev"),
+			Entry("wildcard field", "{[0].labels.*}", "prod a"),
+			Entry("negative index", "{[-1].name}", "dev"),
+			Entry("out of range index", "{[5].name}", ""),
+			Entry("quoted index", "{[0].labels['team']}", "a"),
+			Entry("missing field", "{[0].missing}", ""),
+			Entry("object", "{[0].nodes}", `{"compute":3,"memory":17179869184}`),
+			Entry("boolean", "{[0].hibernating}", "false"),
+			Entry("string literals", `{[0].id}{"\t"}{[0].name}{"\n"}`, "abc\tprod\n"),
+			Entry("string literal with braces", `{"{"}{[0].id}{"}"}`, "{abc}"),
+			Entry("text", "id={[0].id}", "id=abc"),
+			Entry("range", `{range [*]}{.id}:{.nodes.compute}{"\n"}{end}`, "abc:3\ndef:1000000\n"),
+			Entry("nested range", `{range [*]}{.id}{range .zones[*]} {@}{end};{end}`,
+				"abc us-east-1a us-east-1b;def;"),
+		)
+
+		DescribeTable("rejects invalid templates",
+			func(template string, message string) {
+				_, err := output.Format(resource, "jsonpath="+template)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("unclosed action", "{[0].id", "Unclosed action"),
+			Entry("unexpected end", "{[0].id}{end}", "Unexpected '{end}'"),
+			Entry("missing end", "{range [*]}{.id}", "Missing '{end}'"),
+			Entry("unclosed index", "{[0.id}", "Unclosed index"),
+			Entry("invalid index", "{[a]}", "Invalid array index 'a'"),
+			Entry("invalid string literal", `{"\q"}`, "Invalid string literal"),
+			Entry("invalid expression", "{name}", "Invalid JSONPath expression"),
+		)
+	})
+
+	Context("custom-columns", func() {
+		It("prints a table with the given columns", func() {
+			Expect(format("custom-columns=ID:.id,COMPUTE:.nodes.compute,ZONES:.zones[*]")).To(Equal("" +
+				"ID   COMPUTE  ZONES\n" +
+				"abc  3        us-east-1a,us-east-1b\n" +
+				"def  1000000  <none>\n"))
+		})
+
+		It("accepts paths in braces", func() {
+			Expect(format("custom-columns=NAME:{.name}")).To(Equal("NAME\nprod\ndev\n"))
+		})
+
+		It("prints a single object as one row", func() {
+			resource = json.RawMessage(`{"id": "abc"}`)
+			Expect(format("custom-columns=ID:.id")).To(Equal("ID\nabc\n"))
+		})
+
+		DescribeTable("rejects invalid specs",
+			func(spec string) {
+				_, err := output.Format(resource, "custom-columns="+spec)
+				Expect(err).To(MatchError(ContainSubstring("Expected custom column in the format")))
+			},
+			Entry("without path", "ID"),
+			Entry("with empty header", ":.id"),
+			Entry("with empty path", "ID:"),
+		)
+	})
+
+	Context("number formatting", func() {
+		DescribeTable("prints numbers as they are written",
+			func(output string, expected string) {
+				Expect(format(output)).To(ContainSubstring(expected))
+			},
+			Entry("jsonpath", "jsonpath={[0].nodes.memory}", "17179869184"),
+			Entry("custom-columns", "custom-columns=COMPUTE:.nodes.compute", "1000000"),
+			Entry("go-template", "go-template={{range .}}{{.nodes.memory}} {{end}}", "17179869184 0.5 "),
+			Entry("csv", "csv", "1000000"),
+		)
+	})
+
+	Context("csv", func() {
+		It("prints one column per attribute", func() {
+			Expect(format("csv")).To(Equal("" +
+				"id,name,nodes.compute,nodes.memory,labels.team,labels.env,zones,hibernating\n" +
+				"abc,prod,3,17179869184,a,prod,\"us-east-1a,us-east-1b\",false\n" +
+				"def,dev,1000000,0.5,,,,\n"))
+		})
+	})
+
+	DescribeTable("rejects invalid formats",
+		func(format string, message string) {
+			_, err := output.Format(resource, format)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("unknown", "xml", "Unknown format 'xml'"),
+		Entry("wide", "wide", "Unknown format 'wide'"),
+		Entry("argument of json", "json=x", "Format 'json' doesn't take an argument"),
+		Entry("empty jsonpath", "jsonpath=", "Expected a JSONPath expression"),
+		Entry("empty custom-columns", "custom-columns=", "Expected a list of columns"),
+		Entry("empty go-template", "go-template=", "Expected a template"),
+		Entry("empty go-template-file", "go-template-file=", "Expected the path of a template file"),
+		Entry("invalid go-template", "go-template={{.id", "Failed to parse template"),
+	)
+})
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the registry of functions used to convert resources to JSON before they
+// are printed in the format requested with the '--output' command line option.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var (
+	writerType = reflect.TypeOf((*io.Writer)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// marshallers contains the functions used to marshal each type of resource, indexed by the type
+// of the resource they accept.
+var marshallers = map[reflect.Type]reflect.Value{}
+
+// Register adds marshal functions to the registry. Each function must have the same signature as
+// the marshal functions generated by the OCM SDK, for example:
+//
+//	func MarshalCluster(object *Cluster, writer io.Writer) error
+//
+// Resources whose type has no registered function are marshalled using the standard JSON
+// encoder, so types defined by rosa can implement json.Marshaler instead.
+func Register(fns ...interface{}) {
+	for _, fn := range fns {
+		value := reflect.ValueOf(fn)
+		t := value.Type()
+		if t.Kind() != reflect.Func ||
+			t.NumIn() != 2 || t.In(1) != writerType ||
+			t.NumOut() != 1 || t.Out(0) != errorType {
+			panic(fmt.Sprintf("Marshal function has invalid signature %s", t))
+		}
+		marshallers[t.In(0)] = value
+	}
+}
+
+// marshal writes the JSON representation of the resource to the given writer.
+func marshal(resource interface{}, writer io.Writer) error {
+	if fn, ok := marshallers[reflect.TypeOf(resource)]; ok {
+		result := fn.Call([]reflect.Value{reflect.ValueOf(resource), reflect.ValueOf(writer)})
+		if err, _ := result[0].Interface().(error); err != nil {
+			return err
+		}
+		return nil
+	}
+	return json.NewEncoder(writer).Encode(resource)
+}
+
+func init() {
+	Register(
+		amsv1.MarshalAccount,
+		amsv1.MarshalOrganization,
+		cmv1.MarshalAddOn,
+		cmv1.MarshalAddOnList,
+		cmv1.MarshalAddOnInstallation,
+		cmv1.MarshalAddOnInstallationList,
+		cmv1.MarshalAddOnParameterList,
+		cmv1.MarshalCloudRegion,
+		cmv1.MarshalCloudRegionList,
+		cmv1.MarshalCluster,
+		cmv1.MarshalClusterList,
+		cmv1.MarshalClusterStatus,
+		cmv1.MarshalIdentityProvider,
+		cmv1.MarshalIdentityProviderList,
+		cmv1.MarshalIngress,
+		cmv1.MarshalIngressList,
+		cmv1.MarshalLog,
+		cmv1.MarshalMachinePool,
+		cmv1.MarshalMachinePoolList,
+		cmv1.MarshalMachineType,
+		cmv1.MarshalMachineTypeList,
+		cmv1.MarshalUpgradePolicy,
+		cmv1.MarshalUpgradePolicyList,
+		cmv1.MarshalUpgradePolicyState,
+		cmv1.MarshalUser,
+		cmv1.MarshalUserList,
+		cmv1.MarshalVersion,
+		cmv1.MarshalVersionList,
+	)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to print resources as tables, either with user-defined
+// columns or with one column per attribute of the resource.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// Attributes that are part of every API object and carry no useful information in a table
+var skippedAttributes = map[string]bool{
+	"kind": true,
+	"href": true,
+}
+
+// rows returns the items of a list, or the object itself when it isn't a list.
+func rows(data interface{}) []interface{} {
+	if items, ok := data.([]interface{}); ok {
+		return items
+	}
+	return []interface{}{data}
+}
+
+// parseCustomColumns returns the headers and paths of the columns in a 'HEADER:.path,...' spec.
+func parseCustomColumns(spec string) ([]string, []string, error) {
+	var headers, paths []string
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, nil, fmt.Errorf("Expected custom column in the format 'HEADER:.path', got '%s'", column)
+		}
+		headers = append(headers, parts[0])
+		paths = append(paths, strings.Trim(parts[1], "{}"))
+	}
+	return headers, paths, nil
+}
+
+// printCustomColumns prints a table with the columns given in a 'HEADER:.path,...' spec.
+func printCustomColumns(spec string, data interface{}) (string, error) {
+	headers, paths, err := parseCustomColumns(spec)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	writer := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "%s\n", strings.Join(headers, "\t"))
+	for _, row := range rows(data) {
+		values := make([]string, len(paths))
+		for i, path := range paths {
+			results, err := evalJSONPath(path, data, row)
+			if err != nil {
+				return "", err
+			}
+			texts := make([]string, len(results))
+			for j, result := range results {
+				texts[j] = formatValue(result)
+			}
+			values[i] = strings.Join(texts, ",")
+			if values[i] == "" {
+				values[i] = "<none>"
+			}
+		}
+		fmt.Fprintf(writer, "%s\n", strings.Join(values, "\t"))
+	}
+	writer.Flush()
+	return out.String(), nil
+}
+
+// flatten returns the column names and values of each row, with one column for each scalar
+// attribute of the resource. Nested attributes are named using their dotted path, and columns
+// appear in the order that the attributes are first seen.
+func flatten(body []byte) ([]string, []map[string]string, error) {
+	// Wrap the body so that both lists and single objects can be decoded preserving the order
+	// of the attributes
+	wrapper := ordered.NewOrderedMap()
+	err := json.Unmarshal([]byte(fmt.Sprintf(`{"items":%s}`, body)), wrapper)
+	if err != nil {
+		return nil, nil, err
+	}
+	var items []interface{}
+	switch typed := wrapper.Get("items").(type) {
+	case []interface{}:
+		items = typed
+	default:
+		items = []interface{}{typed}
+	}
+
+	columns := []string{}
+	seen := map[string]bool{}
+	values := []map[string]string{}
+	for _, item := range items {
+		row := map[string]string{}
+		flattenValue("", item, row, func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		})
+		values = append(values, row)
+	}
+	return columns, values, nil
+}
+
+func flattenValue(prefix string, value interface{}, row map[string]string, addColumn func(string)) {
+	object, ok := value.(*ordered.OrderedMap)
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		addColumn(prefix)
+		row[prefix] = formatFlatValue(value)
+		return
+	}
+	iter := object.EntriesIter()
+	for {
+		pair, ok := iter()
+		if !ok {
+			break
+		}
+		if skippedAttributes[pair.Key] {
+			continue
+		}
+		column := pair.Key
+		if prefix != "" {
+			column = prefix + "." + pair.Key
+		}
+		flattenValue(column, pair.Value, row, addColumn)
+	}
+}
+
+// formatFlatValue joins lists of scalars with commas, and prints any other list as JSON.
+func formatFlatValue(value interface{}) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return formatValue(value)
+	}
+	texts := make([]string, len(list))
+	for i, item := range list {
+		if _, isObject := item.(*ordered.OrderedMap); isObject {
+			return formatValue(list)
+		}
+		texts[i] = formatValue(item)
+	}
+	return strings.Join(texts, ",")
+}
+
+// printCSV prints the resource in CSV format with one column for each attribute.
+func printCSV(body []byte) (string, error) {
+	columns, values, err := flatten(body)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	err = writer.Write(columns)
+	if err != nil {
+		return "", err
+	}
+	for _, row := range values {
+		fields := make([]string, len(columns))
+		for i, column := range columns {
+			fields[i] = row[column]
+		}
+		err = writer.Write(fields)
+		if err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	return out.String(), writer.Error()
+}